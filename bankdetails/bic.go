@@ -0,0 +1,34 @@
+package bankdetails
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+)
+
+const bicKind = "BIC"
+
+var bicRegexp = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// ValidateBIC checks the BIC structure, 4 letters of bank code, the ISO
+// country code, 2 characters of location and an optional 3 characters
+// branch code.
+func ValidateBIC(bic string) error {
+	if !bicRegexp.MatchString(bic) {
+		return NewErrInvalidValue(bicKind, bic, "must be 8 or 11 characters: bank code, country, location and optional branch")
+	}
+	if country := bic[4:6]; !IsCountry(country) {
+		return NewErrInvalidValue(bicKind, bic, fmt.Sprintf("unknown country %s", country))
+	}
+	return nil
+}
+
+// GenerateBIC returns a random valid 8 characters BIC of the country.
+func GenerateBIC(r *rand.Rand, country string) (string, error) {
+	if !IsCountry(country) {
+		return "", NewErrInvalidValue("country", country, "unknown country")
+	}
+	bankCode := randomString(r, characterSet("a"), 4)
+	location := randomString(r, characterSet("c"), 2)
+	return bankCode + country + location, nil
+}
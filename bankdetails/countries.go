@@ -0,0 +1,65 @@
+package bankdetails
+
+import "strings"
+
+// isoCountries holds the ISO 3166-1 alpha-2 country codes, plus XK that
+// SWIFT uses for Kosovo.
+var isoCountries = toSet(strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ
+	BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR
+	CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+	GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU
+	ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ
+	LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+	MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF
+	PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI
+	SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR
+	TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS XK YE YT ZA ZM ZW
+`))
+
+// ibanLengths is the IBAN length of each country of the SWIFT IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24,
+	"ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24, "SC": 31,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24,
+	"TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// bbanFormats is the BBAN structure, in SWIFT notation, of the countries
+// the account API supports. n is a digit, a an upper case letter and c
+// any of both.
+var bbanFormats = map[string]string{
+	"BE": "3!n7!n2!n",
+	"CH": "5!n12!c",
+	"DE": "8!n10!n",
+	"ES": "4!n4!n1!n1!n10!n",
+	"FR": "5!n5!n11!c2!n",
+	"GB": "4!a6!n8!n",
+	"GR": "3!n4!n16!c",
+	"IE": "4!a6!n8!n",
+	"IT": "1!a5!n5!n12!c",
+	"LU": "3!n13!c",
+	"NL": "4!a10!n",
+	"PL": "8!n16!n",
+	"PT": "4!n4!n11!n2!n",
+}
+
+// IsCountry returns whether the code is an ISO 3166-1 alpha-2 country code.
+func IsCountry(code string) bool {
+	_, ok := isoCountries[code]
+	return ok
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,35 @@
+// Package bankdetails validates and generates bank identifiers: IBANs,
+// BICs and UK sort code and account number pairs.
+package bankdetails
+
+import "fmt"
+
+// ErrInvalidValue is returned when a bank identifier is not valid.
+type ErrInvalidValue struct {
+	kind   string
+	value  string
+	reason string
+}
+
+func NewErrInvalidValue(kind, value, reason string) error {
+	return ErrInvalidValue{kind, value, reason}
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf(
+		"Invalid %s '%s': %s",
+		e.kind,
+		e.value,
+		e.reason,
+	)
+}
+
+// Reason returns why the value is not valid.
+func (e ErrInvalidValue) Reason() string {
+	return e.reason
+}
+
+func (e ErrInvalidValue) Is(target error) bool {
+	t, ok := target.(ErrInvalidValue)
+	return ok && (t == ErrInvalidValue{} || t == e)
+}
@@ -0,0 +1,127 @@
+package bankdetails
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const ibanKind = "IBAN"
+
+var (
+	ibanRegexp       = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+	bbanFormatRegexp = regexp.MustCompile(`([0-9]+)!([nac])`)
+	ninetySeven      = big.NewInt(97)
+)
+
+// NormalizeIBAN returns the IBAN in electronic format, upper case and
+// without spaces.
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// ValidateIBAN checks the IBAN structure, its country length and BBAN
+// format when known, and its mod-97 check digits. Spaces are ignored.
+func ValidateIBAN(iban string) error {
+	normalized := NormalizeIBAN(iban)
+	if !ibanRegexp.MatchString(normalized) {
+		return NewErrInvalidValue(ibanKind, iban, "malformed IBAN")
+	}
+	country := normalized[:2]
+	length, ok := ibanLengths[country]
+	if !ok {
+		return NewErrInvalidValue(ibanKind, iban, fmt.Sprintf("country %s doesn't use IBAN", country))
+	}
+	if len(normalized) != length {
+		return NewErrInvalidValue(ibanKind, iban, fmt.Sprintf("length must be %d for country %s", length, country))
+	}
+	if format, ok := bbanFormats[country]; ok && !bbanRegexp(format).MatchString(normalized[4:]) {
+		return NewErrInvalidValue(ibanKind, iban, fmt.Sprintf("BBAN must match %s for country %s", format, country))
+	}
+	if ibanRemainder(normalized[4:]+normalized[:4]) != 1 {
+		return NewErrInvalidValue(ibanKind, iban, "wrong check digits")
+	}
+	return nil
+}
+
+// GenerateIBAN returns a random valid IBAN of the country.
+func GenerateIBAN(r *rand.Rand, country string) (string, error) {
+	length, ok := ibanLengths[country]
+	if !ok {
+		return "", NewErrInvalidValue("country", country, "country doesn't use IBAN")
+	}
+	format, ok := bbanFormats[country]
+	if !ok {
+		format = fmt.Sprintf("%d!n", length-4)
+	}
+	bban := randomBBAN(r, format)
+	checkDigits := 98 - ibanRemainder(bban+country+"00")
+	return fmt.Sprintf("%s%02d%s", country, checkDigits, bban), nil
+}
+
+// ibanRemainder returns the mod-97 of the value with its letters replaced
+// by numbers, A is 10, B is 11 and so on.
+func ibanRemainder(value string) int {
+	var digits strings.Builder
+	for _, char := range value {
+		if char >= 'A' && char <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(char-'A') + 10))
+		} else {
+			digits.WriteRune(char)
+		}
+	}
+	number, _ := new(big.Int).SetString(digits.String(), 10)
+	return int(new(big.Int).Mod(number, ninetySeven).Int64())
+}
+
+func bbanRegexp(format string) *regexp.Regexp {
+	pattern := bbanFormatRegexp.ReplaceAllStringFunc(format, func(token string) string {
+		parts := bbanFormatRegexp.FindStringSubmatch(token)
+		return fmt.Sprintf("%s{%s}", characterClass(parts[2]), parts[1])
+	})
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+func randomBBAN(r *rand.Rand, format string) string {
+	var bban strings.Builder
+	for _, parts := range bbanFormatRegexp.FindAllStringSubmatch(format, -1) {
+		length, _ := strconv.Atoi(parts[1])
+		bban.WriteString(randomString(r, characterSet(parts[2]), length))
+	}
+	return bban.String()
+}
+
+func characterClass(kind string) string {
+	switch kind {
+	case "n":
+		return "[0-9]"
+	case "a":
+		return "[A-Z]"
+	}
+	return "[A-Z0-9]"
+}
+
+func characterSet(kind string) string {
+	const (
+		digits  = "0123456789"
+		letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	)
+	switch kind {
+	case "n":
+		return digits
+	case "a":
+		return letters
+	}
+	return digits + letters
+}
+
+func randomString(r *rand.Rand, characters string, length int) string {
+	value := make([]byte, length)
+	for i := range value {
+		value[i] = characters[r.Intn(len(characters))]
+	}
+	return string(value)
+}
@@ -0,0 +1,242 @@
+package bankdetails
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	ukAccountKind      = "UK account"
+	weightsCount       = 14
+	maxGenerationTries = 1000
+
+	methodMod10 = "MOD10"
+	methodMod11 = "MOD11"
+	methodDblAl = "DBLAL"
+)
+
+var (
+	sortCodeRegexp      = regexp.MustCompile(`^[0-9]{6}$`)
+	accountNumberRegexp = regexp.MustCompile(`^[0-9]{6,8}$`)
+	// implementedExceptions are the VocaLink exception codes the checker
+	// applies, the ranges with other exceptions, 2, 5, 9 and 14, are
+	// skipped. A sort code with only skipped ranges can't be checked and
+	// every account number of it is valid, as for sort codes out of the
+	// table.
+	implementedExceptions = map[int]bool{0: true, 1: true, 3: true, 4: true, 6: true, 7: true, 8: true, 10: true, 11: true, 12: true, 13: true}
+)
+
+// ModulusChecker validates UK sort code and account number pairs with the
+// VocaLink modulus checking algorithm, using the weights table published
+// by VocaLink (valacdos.txt).
+type ModulusChecker struct {
+	rows []modulusRow
+}
+
+type modulusRow struct {
+	start     int
+	end       int
+	method    string
+	weights   [weightsCount]int
+	exception int
+}
+
+// ParseModulusWeights reads a VocaLink weights table, one range per line:
+// start and end sort codes, method, the 14 weights and an optional
+// exception code.
+func ParseModulusWeights(r io.Reader) (*ModulusChecker, error) {
+	checker := &ModulusChecker{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		row, err := parseModulusRow(fields)
+		if err != nil {
+			return nil, fmt.Errorf("modulus weights line %d: %w", lineNumber, err)
+		}
+		checker.rows = append(checker.rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checker, nil
+}
+
+func parseModulusRow(fields []string) (modulusRow, error) {
+	row := modulusRow{}
+	if len(fields) != 3+weightsCount && len(fields) != 4+weightsCount {
+		return row, fmt.Errorf("expected %d or %d fields, got %d", 3+weightsCount, 4+weightsCount, len(fields))
+	}
+	var err error
+	if row.start, err = strconv.Atoi(fields[0]); err != nil {
+		return row, err
+	}
+	if row.end, err = strconv.Atoi(fields[1]); err != nil {
+		return row, err
+	}
+	row.method = fields[2]
+	if row.method != methodMod10 && row.method != methodMod11 && row.method != methodDblAl {
+		return row, fmt.Errorf("unknown method %s", row.method)
+	}
+	for i := 0; i < weightsCount; i++ {
+		if row.weights[i], err = strconv.Atoi(fields[3+i]); err != nil {
+			return row, err
+		}
+	}
+	if len(fields) == 4+weightsCount {
+		if row.exception, err = strconv.Atoi(fields[3+weightsCount]); err != nil {
+			return row, err
+		}
+	}
+	return row, nil
+}
+
+// Validate checks the account number against every weights range of the
+// sort code. Sort codes out of the table can't be checked and are valid,
+// as the VocaLink specification states, and so are the sort codes whose
+// ranges all have exceptions the checker doesn't implement. Account
+// numbers of 6 or 7 digits are padded with zeros.
+func (c *ModulusChecker) Validate(sortCode, accountNumber string) error {
+	value := sortCode + " " + accountNumber
+	if !sortCodeRegexp.MatchString(sortCode) {
+		return NewErrInvalidValue(ukAccountKind, value, "sort code must be 6 digits")
+	}
+	if !accountNumberRegexp.MatchString(accountNumber) {
+		return NewErrInvalidValue(ukAccountKind, value, "account number must be 6 to 8 digits")
+	}
+	accountNumber = fmt.Sprintf("%08s", accountNumber)
+	code, _ := strconv.Atoi(sortCode)
+
+	rows := c.rowsFor(code)
+	passed := 0
+	failedMethod := ""
+	for _, row := range rows {
+		if !implementedExceptions[row.exception] {
+			continue
+		}
+		digits := toDigits(sortCode + accountNumber)
+		// Exception 3: the check isn't needed when c is 6 or 9
+		if row.exception == 3 && (digits[8] == 6 || digits[8] == 9) {
+			continue
+		}
+		ok, checked := row.check(digits)
+		if !checked {
+			continue
+		}
+		if ok {
+			passed++
+		} else if failedMethod == "" {
+			failedMethod = row.method
+		}
+	}
+	if failedMethod == "" || (passed > 0 && isAlternativePair(rows)) {
+		return nil
+	}
+	return NewErrInvalidValue(ukAccountKind, value, fmt.Sprintf("%s modulus check failed", failedMethod))
+}
+
+// isAlternativePair tells whether the account is valid when any of the two
+// checks of the sort code passes, exceptions 10 and 11 or 12 and 13.
+func isAlternativePair(rows []modulusRow) bool {
+	if len(rows) != 2 {
+		return false
+	}
+	first, second := rows[0].exception, rows[1].exception
+	return (first == 10 && second == 11) || (first == 12 && second == 13)
+}
+
+func (c *ModulusChecker) rowsFor(sortCode int) []modulusRow {
+	rows := []modulusRow{}
+	for _, row := range c.rows {
+		if sortCode >= row.start && sortCode <= row.end {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// check runs the row method over the sort code and account digits, u to
+// z and a to h in the VocaLink notation. It returns false as checked when
+// an exception means the account can't be checked.
+func (row modulusRow) check(digits []int) (ok bool, checked bool) {
+	const a, b, g, h = 6, 7, 12, 13
+	weights := row.weights
+	switch row.exception {
+	case 6:
+		// Foreign currency accounts can't be checked
+		if digits[a] >= 4 && digits[a] <= 8 && digits[g] == digits[h] {
+			return false, false
+		}
+	case 7:
+		if digits[g] == 9 {
+			zeroiseWeights(&weights, b)
+		}
+	case 8:
+		copy(digits, toDigits("090126"))
+	case 10:
+		if ((digits[a] == 0 && digits[b] == 9) || (digits[a] == 9 && digits[b] == 9)) && digits[g] == 9 {
+			zeroiseWeights(&weights, b)
+		}
+	}
+
+	total := 0
+	for i, digit := range digits {
+		product := digit * weights[i]
+		if row.method == methodDblAl {
+			product = product/10 + product%10
+		}
+		total += product
+	}
+	if row.exception == 1 {
+		total += 27
+	}
+
+	switch row.method {
+	case methodMod11:
+		if row.exception == 4 {
+			return total%11 == digits[g]*10+digits[h], true
+		}
+		return total%11 == 0, true
+	default:
+		return total%10 == 0, true
+	}
+}
+
+// GenerateUKAccount returns a random sort code of the weights table and an
+// account number passing its modulus checks.
+func (c *ModulusChecker) GenerateUKAccount(r *rand.Rand) (string, string, error) {
+	if len(c.rows) == 0 {
+		return "", "", fmt.Errorf("empty modulus weights table")
+	}
+	for try := 0; try < maxGenerationTries; try++ {
+		row := c.rows[r.Intn(len(c.rows))]
+		sortCode := fmt.Sprintf("%06d", row.start+r.Intn(row.end-row.start+1))
+		accountNumber := randomString(r, characterSet("n"), 8)
+		if c.Validate(sortCode, accountNumber) == nil {
+			return sortCode, accountNumber, nil
+		}
+	}
+	return "", "", fmt.Errorf("no valid account found after %d tries", maxGenerationTries)
+}
+
+// zeroiseWeights sets to zero the weights from u up to the index.
+func zeroiseWeights(weights *[weightsCount]int, to int) {
+	for i := 0; i <= to; i++ {
+		weights[i] = 0
+	}
+}
+
+func toDigits(value string) []int {
+	digits := make([]int, len(value))
+	for i, char := range value {
+		digits[i] = int(char - '0')
+	}
+	return digits
+}
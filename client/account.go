@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// FetchAccount fetches an account and returns it with typed attributes.
+func FetchAccount(ctx context.Context, c Client, id string) (*resources.AccountResource, error) {
+	return NewTypedClient[resources.AccountAttributes](c).Fetch(ctx, id)
+}
+
+// CreateAccount creates an account from typed attributes and returns the
+// account created by the server.
+func CreateAccount(ctx context.Context, c Client, account resources.AccountResource, options ...CreateOption) (*resources.AccountResource, error) {
+	return NewTypedClient[resources.AccountAttributes](c).Create(ctx, account, options...)
+}
+
+// UpdateAccount updates the account attributes, the account version must be
+// the current one.
+func UpdateAccount(ctx context.Context, c Client, account resources.AccountResource) (*resources.AccountResource, error) {
+	return NewTypedClient[resources.AccountAttributes](c).Update(ctx, account)
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request go through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a few trial requests go through to check if the
+	// server is back.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings defines when a CircuitBreaker opens and closes.
+// A request fails when the http client returns an error, other than the
+// cancellation of its context, or the response status code is 5XX.
+type CircuitBreakerSettings struct {
+	// FailureRatio opens the circuit when the ratio of failed requests in
+	// the current window reaches it, it must be greater than 0 and up to 1.
+	// It's 0.5 by default.
+	FailureRatio float64
+	// MinRequests is the number of requests in the window needed before
+	// opening the circuit, so a single failure doesn't open it. It's 10 by
+	// default.
+	MinRequests int
+	// Window is the period the requests are counted in, the counts are
+	// reset when it's over. It's 10 seconds by default.
+	Window time.Duration
+	// CoolDown is the time the circuit stays open before going half-open,
+	// 30 seconds by default.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of trial requests let through in the
+	// half-open state, the circuit closes when all of them succeed and
+	// opens again as soon as one fails. It's 1 by default.
+	HalfOpenRequests int
+	// OnStateChange is called on every state change, for instance to alert.
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerSettings opens the circuit when half of the requests
+// of the last 10 seconds failed, with at least 10 requests, and tries again
+// after 30 seconds with a single request.
+func DefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		FailureRatio:     0.5,
+		MinRequests:      10,
+		Window:           10 * time.Second,
+		CoolDown:         30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// CircuitBreaker fails the requests fast with ErrCircuitOpen while the
+// server is failing, instead of waiting for every request to fail. It's
+// safe for concurrent use and can be shared by several clients of the same
+// server.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu               sync.Mutex
+	state            CircuitState
+	windowStart      time.Time
+	requests         int
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	halfOpenSuccess  int
+}
+
+// NewCircuitBreaker returns a closed circuit breaker. The settings out of
+// their range, zero included, are replaced with the
+// DefaultCircuitBreakerSettings ones, so a settings literal only needs the
+// fields to change.
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerSettings()
+	if settings.FailureRatio <= 0 || settings.FailureRatio > 1 {
+		settings.FailureRatio = defaults.FailureRatio
+	}
+	if settings.MinRequests < 1 {
+		settings.MinRequests = defaults.MinRequests
+	}
+	if settings.Window <= 0 {
+		settings.Window = defaults.Window
+	}
+	if settings.CoolDown <= 0 {
+		settings.CoolDown = defaults.CoolDown
+	}
+	if settings.HalfOpenRequests < 1 {
+		settings.HalfOpenRequests = defaults.HalfOpenRequests
+	}
+	return &CircuitBreaker{
+		settings:    settings,
+		windowStart: time.Now(),
+	}
+}
+
+// WithCircuitBreaker sends every request attempt through the circuit
+// breaker, the attempts failed with ErrCircuitOpen aren't retried.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(fc *Form3Client) {
+		fc.circuitBreaker = breaker
+	}
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	state, changes := cb.currentState(time.Now())
+	cb.mu.Unlock()
+	cb.notify(changes)
+	return state
+}
+
+func (cb *CircuitBreaker) do(req *http.Request, next RequestHandler) (*http.Response, error) {
+	state, ok := cb.allow()
+	if !ok {
+		return nil, NewErrCircuitOpen(req.Method, req.URL.String())
+	}
+	resp, err := next(req)
+	cb.record(state, isCircuitFailure(resp, err))
+	return resp, err
+}
+
+// allow returns the state the request is let through in, or false when
+// the request has to fail fast.
+func (cb *CircuitBreaker) allow() (CircuitState, bool) {
+	cb.mu.Lock()
+	state, changes := cb.currentState(time.Now())
+	ok := true
+	switch {
+	case state == CircuitOpen:
+		ok = false
+	case state == CircuitHalfOpen && cb.halfOpenInFlight+cb.halfOpenSuccess >= cb.settings.HalfOpenRequests:
+		ok = false
+	case state == CircuitHalfOpen:
+		cb.halfOpenInFlight++
+	}
+	cb.mu.Unlock()
+	cb.notify(changes)
+	return state, ok
+}
+
+// record counts the result of a request let through in the state.
+func (cb *CircuitBreaker) record(state CircuitState, failure bool) {
+	cb.mu.Lock()
+	now := time.Now()
+	current, changes := cb.currentState(now)
+	switch {
+	case state == CircuitHalfOpen && current == CircuitHalfOpen && cb.halfOpenInFlight > 0:
+		cb.halfOpenInFlight--
+		if failure {
+			changes = append(changes, cb.setState(CircuitOpen, now))
+		} else if cb.halfOpenSuccess++; cb.halfOpenSuccess >= cb.settings.HalfOpenRequests {
+			changes = append(changes, cb.setState(CircuitClosed, now))
+		}
+	case state == CircuitClosed && current == CircuitClosed:
+		cb.requests++
+		if failure {
+			cb.failures++
+		}
+		if cb.requests >= cb.settings.MinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= cb.settings.FailureRatio {
+			changes = append(changes, cb.setState(CircuitOpen, now))
+		}
+	}
+	cb.mu.Unlock()
+	cb.notify(changes)
+}
+
+// currentState moves the circuit to half-open when the cool down is over
+// and resets the counts when the window is over. It must be called with
+// the lock held.
+func (cb *CircuitBreaker) currentState(now time.Time) (CircuitState, []stateChange) {
+	changes := []stateChange{}
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) >= cb.settings.CoolDown {
+			changes = append(changes, cb.setState(CircuitHalfOpen, now))
+		}
+	case CircuitClosed:
+		if now.Sub(cb.windowStart) >= cb.settings.Window {
+			cb.resetCounts(now)
+		}
+	}
+	return cb.state, changes
+}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState, now time.Time) stateChange {
+	change := stateChange{cb.state, state}
+	cb.state = state
+	cb.resetCounts(now)
+	if state == CircuitOpen {
+		cb.openedAt = now
+	}
+	return change
+}
+
+func (cb *CircuitBreaker) resetCounts(now time.Time) {
+	cb.windowStart = now
+	cb.requests = 0
+	cb.failures = 0
+	cb.halfOpenInFlight = 0
+	cb.halfOpenSuccess = 0
+}
+
+// notify calls the state change callback, without the lock held so the
+// callback can use the circuit breaker.
+func (cb *CircuitBreaker) notify(changes []stateChange) {
+	if cb.settings.OnStateChange == nil {
+		return
+	}
+	for _, change := range changes {
+		cb.settings.OnStateChange(change.from, change.to)
+	}
+}
+
+func isCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
@@ -2,16 +2,25 @@ package client
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/regiluze/form3-account-api-client/resources"
 )
 
+const filterParameterFormat = "filter[%s]"
+
 var (
+	resourcesEndpointsMu  sync.RWMutex
 	resourcesEndpointsMap = map[resources.ResourceName]string{
-		resources.Account: "organisation/accounts",
+		resources.Account:      resources.AccountAttributes{}.Endpoint(),
+		resources.Organisation: resources.OrganisationAttributes{}.Endpoint(),
+		resources.Payment:      resources.PaymentAttributes{}.Endpoint(),
 	}
+	queryParameterNameReplacer = strings.NewReplacer("%5B", "[", "%5D", "]")
 )
 
 type URLBuilder struct {
@@ -24,8 +33,35 @@ func NewURLBuilder(baseURL string) URLBuilder {
 	}
 }
 
+// RegisterEndpoint sets the endpoint path of a resource, so the client
+// builds its URLs. NewTypedClient registers the endpoint of its resource
+// type when it isn't registered yet.
+func RegisterEndpoint(resourceName resources.ResourceName, endpoint string) {
+	resourcesEndpointsMu.Lock()
+	defer resourcesEndpointsMu.Unlock()
+	resourcesEndpointsMap[resourceName] = endpoint
+}
+
+// registerMissingEndpoint registers the endpoint of a resource unless the
+// resource has one, only taking the write lock the first time.
+func registerMissingEndpoint(resourceName resources.ResourceName, endpoint string) {
+	resourcesEndpointsMu.RLock()
+	_, ok := resourcesEndpointsMap[resourceName]
+	resourcesEndpointsMu.RUnlock()
+	if ok {
+		return
+	}
+	resourcesEndpointsMu.Lock()
+	defer resourcesEndpointsMu.Unlock()
+	if _, ok := resourcesEndpointsMap[resourceName]; !ok {
+		resourcesEndpointsMap[resourceName] = endpoint
+	}
+}
+
 func (u URLBuilder) DoForResource(resourceName resources.ResourceName) string {
+	resourcesEndpointsMu.RLock()
 	endpoint := resourcesEndpointsMap[resourceName]
+	resourcesEndpointsMu.RUnlock()
 	return fmt.Sprintf("%s/%s", u.baseURL, endpoint)
 }
 
@@ -34,17 +70,34 @@ func (u URLBuilder) DoForResourceWithID(resourceName resources.ResourceName, id
 	return fmt.Sprintf("%s/%s", resourceEndpoint, id)
 }
 
-func (u URLBuilder) DoForResourceWithParameters(resourceName resources.ResourceName, parameters map[string]string) string {
+func (u URLBuilder) DoForResourceWithParameters(resourceName resources.ResourceName, parameters url.Values) string {
 	resourceEndpoint := u.DoForResource(resourceName)
 	return fmt.Sprintf("%s%s", resourceEndpoint, u.buildQueryParameters(parameters))
 }
 
-func (u URLBuilder) DoForResourceWithIDAndParameters(resourceName resources.ResourceName, id string, parameters map[string]string) string {
+func (u URLBuilder) DoForResourceWithIDAndParameters(resourceName resources.ResourceName, id string, parameters url.Values) string {
 	resourceEndpoint := u.DoForResourceWithID(resourceName, id)
 	return fmt.Sprintf("%s%s", resourceEndpoint, u.buildQueryParameters(parameters))
 }
 
-func (u URLBuilder) buildQueryParameters(parameters map[string]string) string {
+// DoForLink resolves a resource link returned by the server, usually a path
+// like /v1/organisation/accounts?page[number]=1, against the base URL.
+func (u URLBuilder) DoForLink(link string) (string, error) {
+	base, err := url.Parse(u.baseURL)
+	if err != nil {
+		return "", err
+	}
+	reference, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(reference).String(), nil
+}
+
+// buildQueryParameters escapes parameter names and values, keeping the
+// brackets of names like page[size] readable, and joins multiple values
+// of the same parameter with commas as the Form3 API expects.
+func (u URLBuilder) buildQueryParameters(parameters url.Values) string {
 	flatParams := []string{}
 	paramNames := []string{}
 	for name := range parameters {
@@ -52,7 +105,79 @@ func (u URLBuilder) buildQueryParameters(parameters map[string]string) string {
 	}
 	sort.Strings(paramNames)
 	for _, name := range paramNames {
-		flatParams = append(flatParams, fmt.Sprintf("%s=%s", name, parameters[name]))
+		values := []string{}
+		for _, value := range parameters[name] {
+			values = append(values, url.QueryEscape(value))
+		}
+		flatParams = append(flatParams, fmt.Sprintf(
+			"%s=%s",
+			queryParameterNameReplacer.Replace(url.QueryEscape(name)),
+			strings.Join(values, ","),
+		))
 	}
 	return fmt.Sprintf("?%s", strings.Join(flatParams, "&"))
 }
+
+// buildFilterParameters converts a List filter map into filter[name]
+// query parameters. Values can be strings, booleans, integers, floats or
+// slices of them; any other type returns an ErrInvalidFilterValue error.
+func buildFilterParameters(filter map[string]interface{}) (url.Values, error) {
+	parameters := url.Values{}
+	for name, value := range filter {
+		values, err := filterValueToStrings(value)
+		if err != nil {
+			return nil, NewErrInvalidFilterValue(name, value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		parameters[fmt.Sprintf(filterParameterFormat, name)] = values
+	}
+	return parameters, nil
+}
+
+func filterValueToStrings(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []int:
+		values := []string{}
+		for _, i := range v {
+			values = append(values, strconv.Itoa(i))
+		}
+		return values, nil
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			value, err := filterScalarToString(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+	scalar, err := filterScalarToString(value)
+	if err != nil {
+		return nil, err
+	}
+	return []string{scalar}, nil
+}
+
+func filterScalarToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported filter value type %T", value)
+}
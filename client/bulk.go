@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// BulkMode sets what a bulk operation does when an item fails.
+type BulkMode int
+
+const (
+	// ContinueOnError processes all the items and returns an ErrBulkFailed
+	// error when any of them failed.
+	ContinueOnError BulkMode = iota
+	// FailFast stops scheduling items after the first failure and returns
+	// its error, the items in flight are completed.
+	FailFast
+)
+
+// BulkOptions configures the bulk operations.
+type BulkOptions struct {
+	// Concurrency is the number of items processed at the same time.
+	Concurrency int
+	Mode        BulkMode
+	// OnProgress is called after every processed item, the calls are
+	// serialized so it must return quickly.
+	OnProgress func(progress BulkProgress)
+	// IdempotencyKey returns the idempotency key of the resources created
+	// by BulkCreate, so the creations are retried safely.
+	IdempotencyKey func(resource resources.Resource) string
+}
+
+// DefaultBulkOptions processes 10 items at the same time in
+// ContinueOnError mode.
+func DefaultBulkOptions() BulkOptions {
+	return BulkOptions{
+		Concurrency: 10,
+		Mode:        ContinueOnError,
+	}
+}
+
+// BulkProgress counts the processed items of a bulk operation.
+type BulkProgress struct {
+	Total     int
+	Completed int
+	Failed    int
+}
+
+// BulkResult is the result of an item, in the same position as the item.
+// Data is the resource returned by the server for a successful Create, Err
+// the error of the client. The items that weren't processed have the
+// context error when the context was done, or an ErrBulkSkipped error after
+// a FailFast failure.
+type BulkResult struct {
+	ID   string
+	Data *resources.Resource
+	Err  error
+}
+
+// BulkDeleteItem is a resource to delete with its current version.
+type BulkDeleteItem struct {
+	ID      string
+	Version int
+}
+
+// BulkCreate creates the resources with a pool of workers. It stops
+// scheduling new creations when the context is done, returning its error.
+func BulkCreate(ctx context.Context, c Client, resourceName resources.ResourceName, items []resources.Resource, options BulkOptions) ([]BulkResult, error) {
+	results := make([]BulkResult, len(items))
+	for i, item := range items {
+		results[i].ID = item.ID
+	}
+	err := runBulk(ctx, results, options, func(i int) error {
+		var createOptions []CreateOption
+		if options.IdempotencyKey != nil {
+			createOptions = append(createOptions, WithIdempotencyKey(options.IdempotencyKey(items[i])))
+		}
+		resp, err := c.Create(ctx, resourceName, items[i], createOptions...)
+		if err != nil {
+			return err
+		}
+		results[i].Data = &resp.Data
+		return nil
+	})
+	return results, err
+}
+
+// BulkDelete deletes the resources with a pool of workers. It stops
+// scheduling new deletions when the context is done, returning its error.
+func BulkDelete(ctx context.Context, c Client, resourceName resources.ResourceName, items []BulkDeleteItem, options BulkOptions) ([]BulkResult, error) {
+	results := make([]BulkResult, len(items))
+	for i, item := range items {
+		results[i].ID = item.ID
+	}
+	err := runBulk(ctx, results, options, func(i int) error {
+		return c.Delete(ctx, resourceName, items[i].ID, items[i].Version)
+	})
+	return results, err
+}
+
+// runBulk runs do for every result index with options.Concurrency workers,
+// setting the errors of the results.
+func runBulk(ctx context.Context, results []BulkResult, options BulkOptions, do func(i int) error) error {
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu        sync.Mutex
+		progress  = BulkProgress{Total: len(results)}
+		firstErr  error
+		stop      = make(chan struct{})
+		jobs      = make(chan int)
+		processed = make([]bool, len(results))
+		workers   sync.WaitGroup
+	)
+	complete := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i].Err = err
+		processed[i] = true
+		progress.Completed++
+		if err != nil {
+			progress.Failed++
+			if firstErr == nil {
+				firstErr = err
+				if options.Mode == FailFast {
+					close(stop)
+				}
+			}
+		}
+		if options.OnProgress != nil {
+			options.OnProgress(progress)
+		}
+	}
+
+	for w := 0; w < concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				complete(i, do(i))
+			}
+		}()
+	}
+schedule:
+	for i := range results {
+		select {
+		case <-ctx.Done():
+			break schedule
+		case <-stop:
+			break schedule
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			break schedule
+		case <-stop:
+			break schedule
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	workers.Wait()
+
+	for i := range results {
+		if processed[i] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+		} else {
+			results[i].Err = NewErrBulkSkipped()
+		}
+	}
+	switch {
+	case options.Mode == FailFast && firstErr != nil:
+		return firstErr
+	case ctx.Err() != nil && progress.Completed < len(results):
+		return ctx.Err()
+	case progress.Failed > 0:
+		return NewErrBulkFailed(progress.Failed, len(results))
+	}
+	return nil
+}
@@ -4,19 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/regiluze/form3-account-api-client/resources"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const DefaultMimeType = "application/vnd.api+json"
 
 type Client interface {
 	Fetch(ctx context.Context, resourceName resources.ResourceName, id string) (*resources.DataContainer, error)
-	Create(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource) (*resources.DataContainer, error)
+	Create(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource, options ...CreateOption) (*resources.DataContainer, error)
 	List(ctx context.Context, resourceName resources.ResourceName, filter map[string]interface{}, pageNumber, pageSize int) (*resources.ListDataContainer, error)
 	Delete(ctx context.Context, resourceName resources.ResourceName, id string, version int) error
+	Update(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource) (*resources.DataContainer, error)
 }
 
 type HTTPClient interface {
@@ -24,22 +30,62 @@ type HTTPClient interface {
 }
 
 type Form3Client struct {
-	httpClient HTTPClient
-	urlBuilder URLBuilder
+	httpClient        HTTPClient
+	urlBuilder        URLBuilder
+	retryPolicy       *RetryPolicy
+	signer            *HTTPSigner
+	tokenSource       TokenSource
+	validator         Validator
+	middlewares       []Middleware
+	metrics           MetricsRecorder
+	tracer            trace.Tracer
+	propagator        propagation.TextMapPropagator
+	circuitBreaker    *CircuitBreaker
+	rateLimiter       *RateLimiter
+	userAgent         string
+	operationTimeouts map[Operation]time.Duration
 }
 
-func NewForm3APIClient(baseURL string, httpClient HTTPClient) *Form3Client {
+// Option configures optional Form3Client features.
+type Option func(*Form3Client)
+
+// Validator checks a resource on the client side before creating it, see
+// the validation package.
+type Validator func(resourceName resources.ResourceName, resource resources.Resource) error
+
+// WithValidator validates the resources with the validator before sending
+// them, Create returns the validator error without requesting the server.
+func WithValidator(validator Validator) Option {
+	return func(fc *Form3Client) {
+		fc.validator = validator
+	}
+}
+
+func NewForm3APIClient(baseURL string, httpClient HTTPClient, options ...Option) *Form3Client {
 	urlBuilder := NewURLBuilder(baseURL)
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return &Form3Client{
+	client := &Form3Client{
 		httpClient: httpClient,
 		urlBuilder: urlBuilder,
 	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
 }
 
-func (fc Form3Client) Create(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource) (*resources.DataContainer, error) {
+func (fc Form3Client) Create(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource, options ...CreateOption) (*resources.DataContainer, error) {
+	createOptions := createOptions{}
+	for _, option := range options {
+		option(&createOptions)
+	}
+	if fc.validator != nil {
+		if err := fc.validator(resourceName, resource); err != nil {
+			return nil, err
+		}
+	}
 	data := resources.NewDataContainer(resource)
 	dataB, err := json.Marshal(data)
 	if err != nil {
@@ -50,9 +96,17 @@ func (fc Form3Client) Create(ctx context.Context, resourceName resources.Resourc
 	if err != nil {
 		return nil, err
 	}
+	if createOptions.idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, createOptions.idempotencyKey)
+	}
 
 	responseData := &resources.DataContainer{}
-	if err := fc.makeRequest(ctx, req, responseData); err != nil {
+	if err := fc.makeRequest(ctx, RequestInfo{resourceName, OperationCreate, resource.ID}, req, responseData); err != nil {
+		if createOptions.idempotencyKey != "" && errors.Is(err, ErrConflict{}) {
+			if existing, ok := fc.fetchCreatedResource(ctx, resourceName, resource); ok {
+				return existing, nil
+			}
+		}
 		return nil, err
 	}
 	return responseData, nil
@@ -66,45 +120,67 @@ func (fc Form3Client) Fetch(ctx context.Context, resourceName resources.Resource
 	}
 
 	responseData := &resources.DataContainer{}
-	if err := fc.makeRequest(ctx, req, responseData); err != nil {
+	if err := fc.makeRequest(ctx, RequestInfo{resourceName, OperationFetch, id}, req, responseData); err != nil {
 		return nil, err
 	}
 	return responseData, nil
 }
 
 func (fc Form3Client) List(ctx context.Context, resourceName resources.ResourceName, filter map[string]interface{}, pageNumber, pageSize int) (*resources.ListDataContainer, error) {
-	url := fc.urlBuilder.DoForResourceWithParameters(
-		resourceName,
-		map[string]string{
-			"page[number]": strconv.Itoa(pageNumber),
-			"page[size]":   strconv.Itoa(pageSize),
-			// TODO add the filter query parameter
-		},
-	)
+	parameters, err := buildFilterParameters(filter)
+	if err != nil {
+		return nil, err
+	}
+	parameters.Set("page[number]", strconv.Itoa(pageNumber))
+	parameters.Set("page[size]", strconv.Itoa(pageSize))
+	url := fc.urlBuilder.DoForResourceWithParameters(resourceName, parameters)
+	return fc.listURL(ctx, resourceName, url)
+}
+
+func (fc Form3Client) listURL(ctx context.Context, resourceName resources.ResourceName, url string) (*resources.ListDataContainer, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	responseData := &resources.ListDataContainer{}
-	if err := fc.makeRequest(ctx, req, responseData); err != nil {
+	if err := fc.makeRequest(ctx, RequestInfo{resourceName, OperationList, ""}, req, responseData); err != nil {
 		return nil, err
 	}
 	return responseData, nil
 }
 
 func (fc Form3Client) Delete(ctx context.Context, resourceName resources.ResourceName, id string, version int) error {
-	url := fc.urlBuilder.DoForResourceWithIDAndParameters(
-		resourceName,
-		id,
-		map[string]string{
-			"version": strconv.Itoa(version),
-		},
-	)
+	parameters := url.Values{
+		"version": {strconv.Itoa(version)},
+	}
+	url := fc.urlBuilder.DoForResourceWithIDAndParameters(resourceName, id, parameters)
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
 
-	return fc.makeRequest(ctx, req, nil)
+	return fc.makeRequest(ctx, RequestInfo{resourceName, OperationDelete, id}, req, nil)
+}
+
+// Update sends a PATCH request with the resource attributes to change and
+// its current version, the server rejects it with an ErrVersionMismatch
+// error when the resource has been modified since that version.
+func (fc Form3Client) Update(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource) (*resources.DataContainer, error) {
+	data := resources.NewDataContainer(resource)
+	dataB, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	url := fc.urlBuilder.DoForResourceWithID(resourceName, resource.ID)
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(dataB))
+	if err != nil {
+		return nil, err
+	}
+
+	responseData := &resources.DataContainer{}
+	if err := fc.makeRequest(ctx, RequestInfo{resourceName, OperationUpdate, resource.ID}, req, responseData); err != nil {
+		return nil, err
+	}
+	return responseData, nil
 }
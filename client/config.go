@@ -0,0 +1,455 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvBaseURL        = "FORM3_API_BASE_URL"
+	EnvClientID       = "FORM3_API_CLIENT_ID"
+	EnvClientSecret   = "FORM3_API_CLIENT_SECRET"
+	EnvTokenURL       = "FORM3_API_TOKEN_URL"
+	EnvSigningKeyID   = "FORM3_API_SIGNING_KEY_ID"
+	EnvSigningKeyFile = "FORM3_API_SIGNING_KEY_FILE"
+
+	// DefaultTimeout is the timeout of the http client built by NewClient.
+	DefaultTimeout = 30 * time.Second
+
+	tokenPath = "/oauth2/token"
+)
+
+// Config has the client settings that can be loaded from the environment
+// or a YAML or JSON config file, see WithEnvironment and WithConfigFile.
+// Empty fields don't override the settings of previous options.
+type Config struct {
+	BaseURL        string        `yaml:"base_url"`
+	Timeout        time.Duration `yaml:"timeout"`
+	UserAgent      string        `yaml:"user_agent"`
+	Proxy          string        `yaml:"proxy"`
+	CAFile         string        `yaml:"ca_file"`
+	ClientCertFile string        `yaml:"client_cert_file"`
+	ClientKeyFile  string        `yaml:"client_key_file"`
+	ClientID       string        `yaml:"client_id"`
+	ClientSecret   string        `yaml:"client_secret"`
+	TokenURL       string        `yaml:"token_url"`
+	SigningKeyID   string        `yaml:"signing_key_id"`
+	SigningKeyFile string        `yaml:"signing_key_file"`
+}
+
+// buildConfig has the settings NewClient uses to build the client.
+type buildConfig struct {
+	Config
+	httpClient          HTTPClient
+	tlsConfig           *tls.Config
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	transportOptions    bool
+	operationTimeouts   map[Operation]time.Duration
+	err                 error
+}
+
+// ClientOption is an option of NewClient, either an Option or a
+// BuildOption.
+type ClientOption interface {
+	apply(fc *Form3Client, config *buildConfig)
+}
+
+func (o Option) apply(fc *Form3Client, config *buildConfig) {
+	o(fc)
+}
+
+// BuildOption configures how NewClient builds the client, the type keeps
+// them from being passed to NewForm3APIClient.
+type BuildOption func(*buildConfig)
+
+func (o BuildOption) apply(fc *Form3Client, config *buildConfig) {
+	o(config)
+}
+
+// NewClient creates a client with the options, building an http client
+// with DefaultTimeout unless WithHTTPClient is used. The base URL is
+// FORM3_API_BASE_URL when no option sets it. The settings are validated,
+// and an ErrInvalidConfig error is returned for the first invalid one.
+func NewClient(options ...ClientOption) (*Form3Client, error) {
+	client := &Form3Client{}
+	config := &buildConfig{}
+	for _, option := range options {
+		option.apply(client, config)
+	}
+	if config.err != nil {
+		return nil, config.err
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = os.Getenv(EnvBaseURL)
+	}
+	if err := validateURL("base_url", config.BaseURL); err != nil {
+		return nil, err
+	}
+	client.urlBuilder = NewURLBuilder(config.BaseURL)
+	if config.UserAgent != "" && client.userAgent == "" {
+		client.userAgent = config.UserAgent
+	}
+	client.operationTimeouts = config.operationTimeouts
+
+	httpClient, err := config.buildHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	client.httpClient = httpClient
+	if err := config.buildCredentials(client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// WithBaseURL sets the API base URL, NewClient option.
+func WithBaseURL(baseURL string) BuildOption {
+	return func(config *buildConfig) {
+		config.BaseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the http client, NewClient option. The timeout,
+// connection pool, TLS and proxy options can't be used with it.
+func WithHTTPClient(httpClient HTTPClient) BuildOption {
+	return func(config *buildConfig) {
+		config.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of the whole request in the http client
+// built by NewClient, retries not included, NewClient option.
+func WithTimeout(timeout time.Duration) BuildOption {
+	return func(config *buildConfig) {
+		config.Timeout = timeout
+		config.transportOptions = true
+	}
+}
+
+// WithOperationTimeout sets a timeout for the requests of an operation,
+// retries included, on top of the context deadline, NewClient option.
+func WithOperationTimeout(operation Operation, timeout time.Duration) BuildOption {
+	return func(config *buildConfig) {
+		if timeout <= 0 {
+			config.setError(NewErrInvalidConfig("operation_timeout", "must be positive"))
+			return
+		}
+		if config.operationTimeouts == nil {
+			config.operationTimeouts = map[Operation]time.Duration{}
+		}
+		config.operationTimeouts[operation] = timeout
+	}
+}
+
+// WithConnectionPool sets the connection pool sizes of the http client
+// built by NewClient, zero values keep the http.DefaultTransport ones,
+// NewClient option.
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost, maxConnsPerHost int) BuildOption {
+	return func(config *buildConfig) {
+		config.maxIdleConns = maxIdleConns
+		config.maxIdleConnsPerHost = maxIdleConnsPerHost
+		config.maxConnsPerHost = maxConnsPerHost
+		config.transportOptions = true
+	}
+}
+
+// WithTLSConfig sets the base TLS config of the http client built by
+// NewClient, the CA and client certificate options are added to it,
+// NewClient option.
+func WithTLSConfig(tlsConfig *tls.Config) BuildOption {
+	return func(config *buildConfig) {
+		config.tlsConfig = tlsConfig
+		config.transportOptions = true
+	}
+}
+
+// WithCAFile trusts the PEM certificates of the file, on top of the
+// system ones, NewClient option.
+func WithCAFile(caFile string) BuildOption {
+	return func(config *buildConfig) {
+		config.CAFile = caFile
+	}
+}
+
+// WithClientCertificate sends the PEM client certificate of the files for
+// mutual TLS, NewClient option.
+func WithClientCertificate(certFile, keyFile string) BuildOption {
+	return func(config *buildConfig) {
+		config.ClientCertFile = certFile
+		config.ClientKeyFile = keyFile
+	}
+}
+
+// WithProxy sends the requests through the proxy, NewClient option. The
+// http client built by NewClient uses the proxy environment variables
+// otherwise.
+func WithProxy(proxyURL string) BuildOption {
+	return func(config *buildConfig) {
+		config.Proxy = proxyURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(fc *Form3Client) {
+		fc.userAgent = userAgent
+	}
+}
+
+// WithEnvironment loads the base URL and credentials from the FORM3_API_*
+// environment variables, NewClient option. The client credentials enable
+// OAuth2 authentication and the signing key HTTP signatures.
+func WithEnvironment() BuildOption {
+	return func(config *buildConfig) {
+		config.merge(Config{
+			BaseURL:        os.Getenv(EnvBaseURL),
+			ClientID:       os.Getenv(EnvClientID),
+			ClientSecret:   os.Getenv(EnvClientSecret),
+			TokenURL:       os.Getenv(EnvTokenURL),
+			SigningKeyID:   os.Getenv(EnvSigningKeyID),
+			SigningKeyFile: os.Getenv(EnvSigningKeyFile),
+		})
+	}
+}
+
+// WithConfigFile loads the Config settings of a YAML or JSON file,
+// NewClient option.
+func WithConfigFile(path string) BuildOption {
+	return func(config *buildConfig) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			config.setError(NewErrInvalidConfig("config_file", err.Error()))
+			return
+		}
+		var fileConfig Config
+		if err := yaml.Unmarshal(data, &fileConfig); err != nil {
+			config.setError(NewErrInvalidConfig("config_file", err.Error()))
+			return
+		}
+		config.merge(fileConfig)
+	}
+}
+
+func (c *buildConfig) setError(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *buildConfig) merge(config Config) {
+	fields := []struct {
+		target *string
+		value  string
+	}{
+		{&c.BaseURL, config.BaseURL},
+		{&c.UserAgent, config.UserAgent},
+		{&c.Proxy, config.Proxy},
+		{&c.CAFile, config.CAFile},
+		{&c.ClientCertFile, config.ClientCertFile},
+		{&c.ClientKeyFile, config.ClientKeyFile},
+		{&c.ClientID, config.ClientID},
+		{&c.ClientSecret, config.ClientSecret},
+		{&c.TokenURL, config.TokenURL},
+		{&c.SigningKeyID, config.SigningKeyID},
+		{&c.SigningKeyFile, config.SigningKeyFile},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if config.Timeout != 0 {
+		c.Timeout = config.Timeout
+		c.transportOptions = true
+	}
+}
+
+func (c *buildConfig) buildHTTPClient() (HTTPClient, error) {
+	usesTransport := c.transportOptions || c.Proxy != "" || c.CAFile != "" ||
+		c.ClientCertFile != "" || c.ClientKeyFile != ""
+	if c.httpClient != nil {
+		if usesTransport {
+			return nil, NewErrInvalidConfig("http_client", "can't be used with the timeout, connection pool, TLS and proxy options")
+		}
+		return c.httpClient, nil
+	}
+	if c.Timeout < 0 {
+		return nil, NewErrInvalidConfig("timeout", "can't be negative")
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.maxIdleConns < 0 || c.maxIdleConnsPerHost < 0 || c.maxConnsPerHost < 0 {
+		return nil, NewErrInvalidConfig("connection_pool", "sizes can't be negative")
+	}
+	if c.maxIdleConns > 0 {
+		transport.MaxIdleConns = c.maxIdleConns
+	}
+	if c.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.maxIdleConnsPerHost
+	}
+	if c.maxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = c.maxConnsPerHost
+	}
+	if c.Proxy != "" {
+		if err := validateURL("proxy", c.Proxy); err != nil {
+			return nil, err
+		}
+		proxyURL, _ := url.Parse(c.Proxy)
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	tlsConfig, err := c.buildTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func (c *buildConfig) buildTLSConfig() (*tls.Config, error) {
+	if c.tlsConfig == nil && c.CAFile == "" && c.ClientCertFile == "" && c.ClientKeyFile == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.tlsConfig != nil {
+		tlsConfig = c.tlsConfig.Clone()
+	}
+	if c.CAFile != "" {
+		pemCerts, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, NewErrInvalidConfig("ca_file", err.Error())
+		}
+		pool := tlsConfig.RootCAs
+		if pool == nil {
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		if !pool.AppendCertsFromPEM(pemCerts) {
+			return nil, NewErrInvalidConfig("ca_file", "no PEM certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+			return nil, NewErrInvalidConfig("client_cert_file", "the client certificate and key files are both required")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, NewErrInvalidConfig("client_cert_file", err.Error())
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+	return tlsConfig, nil
+}
+
+// buildCredentials sets the token source or signer of the credentials,
+// unless they are set with WithTokenSource or WithHTTPSigner. A client
+// can't have both.
+func (c *buildConfig) buildCredentials(client *Form3Client) error {
+	if (c.ClientID == "") != (c.ClientSecret == "") {
+		return NewErrInvalidConfig("client_id", "the client id and secret are both required")
+	}
+	if c.ClientID != "" && client.tokenSource == nil {
+		tokenURL := c.TokenURL
+		if tokenURL == "" {
+			tokenURL = strings.TrimSuffix(c.BaseURL, "/") + tokenPath
+		}
+		if err := validateURL("token_url", tokenURL); err != nil {
+			return err
+		}
+		client.tokenSource = NewClientCredentialsTokenSource(
+			ClientCredentials{
+				TokenURL:     tokenURL,
+				ClientID:     c.ClientID,
+				ClientSecret: c.ClientSecret,
+			},
+			client.httpClient,
+		)
+	}
+
+	if (c.SigningKeyID == "") != (c.SigningKeyFile == "") {
+		return NewErrInvalidConfig("signing_key_id", "the signing key id and file are both required")
+	}
+	if c.SigningKeyID != "" && client.signer == nil {
+		privateKey, err := readPrivateKey(c.SigningKeyFile)
+		if err != nil {
+			return NewErrInvalidConfig("signing_key_file", err.Error())
+		}
+		signer, err := NewHTTPSigner(c.SigningKeyID, privateKey)
+		if err != nil {
+			return NewErrInvalidConfig("signing_key_file", err.Error())
+		}
+		client.signer = signer
+	}
+	if client.tokenSource != nil && client.signer != nil {
+		return NewErrInvalidConfig("signing_key_id", "can't be used with the client credentials, both set the Authorization header")
+	}
+	return nil
+}
+
+// readPrivateKey reads a PEM PKCS#8, PKCS#1 or EC private key.
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM private key found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
+
+func validateURL(field, value string) error {
+	if value == "" {
+		return NewErrInvalidConfig(field, "is required")
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewErrInvalidConfig(field, "must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// withOperationTimeout adds the operation timeout to the context, if any.
+func (fc Form3Client) withOperationTimeout(ctx context.Context, operation Operation) (context.Context, context.CancelFunc) {
+	timeout, ok := fc.operationTimeouts[operation]
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -2,10 +2,16 @@ package client
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/regiluze/form3-account-api-client/resources"
+	"github.com/regiluze/form3-account-api-client/validation"
 )
 
+// The error types implement Is so a zero value works as a sentinel, for
+// instance errors.Is(err, ErrNotFound{}) is true for any ErrNotFound error.
+
 // ErrNotFound is returned when getting a 404 status code.
 type ErrNotFound struct {
 	url string
@@ -22,6 +28,11 @@ func (e ErrNotFound) Error() string {
 	)
 }
 
+func (e ErrNotFound) Is(target error) bool {
+	t, ok := target.(ErrNotFound)
+	return ok && (t == ErrNotFound{} || t == e)
+}
+
 // ErrBadRequest is returned when getting a 400 status code.
 type ErrBadRequest struct {
 	method    string
@@ -41,10 +52,22 @@ func (e ErrBadRequest) Error() string {
 	)
 }
 
-func (e ErrBadRequest) getErrorData() resources.BadRequestData {
+func (e ErrBadRequest) Is(target error) bool {
+	t, ok := target.(ErrBadRequest)
+	return ok && (t == ErrBadRequest{} || t == e)
+}
+
+// ErrorData returns the error code and message of the server.
+func (e ErrBadRequest) ErrorData() resources.BadRequestData {
 	return e.errorData
 }
 
+// FieldErrors returns the field errors of the server "validation failure
+// list" message, or nil when the message isn't a failure list.
+func (e ErrBadRequest) FieldErrors() validation.Errors {
+	return validation.ParseServerMessage(e.errorData.ErrorMessage)
+}
+
 // ErrResponseStatusCode is returned when getting a 50X and 40X status codes,
 // less for 400 and 404 status codes.
 type ErrResponseStatusCode struct {
@@ -65,3 +88,259 @@ func (e ErrResponseStatusCode) Error() string {
 		e.StatusCode,
 	)
 }
+
+// Is matches the target fields that aren't empty, so
+// errors.Is(err, ErrResponseStatusCode{StatusCode: 503}) is true for any
+// request getting a 503 status code.
+func (e ErrResponseStatusCode) Is(target error) bool {
+	t, ok := target.(ErrResponseStatusCode)
+	return ok &&
+		(t.method == "" || t.method == e.method) &&
+		(t.url == "" || t.url == e.url) &&
+		(t.StatusCode == 0 || t.StatusCode == e.StatusCode)
+}
+
+// ErrConflict is returned when getting a 409 status code creating a
+// resource, usually because a resource with the same id already exists.
+// It wraps the ErrResponseStatusCode error of the 409 status code.
+type ErrConflict struct {
+	method  string
+	url     string
+	message string
+}
+
+func NewErrConflict(method, url, message string) error {
+	return ErrConflict{method, url, message}
+}
+
+func (e ErrConflict) Error() string {
+	return fmt.Sprintf(
+		"Conflict (%s, %s): %s",
+		e.method,
+		e.url,
+		e.message,
+	)
+}
+
+// Message returns the error message of the server.
+func (e ErrConflict) Message() string {
+	return e.message
+}
+
+func (e ErrConflict) Is(target error) bool {
+	t, ok := target.(ErrConflict)
+	return ok && (t == ErrConflict{} || t == e)
+}
+
+func (e ErrConflict) Unwrap() error {
+	return NewErrResponseStatusCode(e.method, e.url, http.StatusConflict)
+}
+
+// ErrVersionMismatch is returned when getting a 409 status code deleting
+// or updating a resource because the version sent isn't the current one.
+// It wraps the ErrResponseStatusCode error of the 409 status code.
+type ErrVersionMismatch struct {
+	method  string
+	url     string
+	message string
+}
+
+func NewErrVersionMismatch(method, url, message string) error {
+	return ErrVersionMismatch{method, url, message}
+}
+
+func (e ErrVersionMismatch) Error() string {
+	return fmt.Sprintf(
+		"Version mismatch (%s, %s): %s",
+		e.method,
+		e.url,
+		e.message,
+	)
+}
+
+// Message returns the error message of the server.
+func (e ErrVersionMismatch) Message() string {
+	return e.message
+}
+
+func (e ErrVersionMismatch) Is(target error) bool {
+	t, ok := target.(ErrVersionMismatch)
+	return ok && (t == ErrVersionMismatch{} || t == e)
+}
+
+func (e ErrVersionMismatch) Unwrap() error {
+	return NewErrResponseStatusCode(e.method, e.url, http.StatusConflict)
+}
+
+// ErrCircuitOpen is returned without sending the request when the circuit
+// breaker is open, because the server has been failing.
+type ErrCircuitOpen struct {
+	method string
+	url    string
+}
+
+func NewErrCircuitOpen(method, url string) error {
+	return ErrCircuitOpen{method, url}
+}
+
+func (e ErrCircuitOpen) Error() string {
+	return fmt.Sprintf(
+		"Circuit open, request not sent (%s, %s)",
+		e.method,
+		e.url,
+	)
+}
+
+func (e ErrCircuitOpen) Is(target error) bool {
+	t, ok := target.(ErrCircuitOpen)
+	return ok && (t == ErrCircuitOpen{} || t == e)
+}
+
+// ErrRateLimited is returned when getting a 429 status code, with the
+// Retry-After time and the rate limit headers of the response, zero when
+// they are missing. It wraps the ErrResponseStatusCode error of the 429
+// status code.
+type ErrRateLimited struct {
+	method     string
+	url        string
+	retryAfter time.Duration
+	limit      int
+	remaining  int
+	reset      string
+}
+
+func NewErrRateLimited(method, url string, retryAfter time.Duration, limit, remaining int, reset string) error {
+	return ErrRateLimited{method, url, retryAfter, limit, remaining, reset}
+}
+
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf(
+		"Rate limited (%s, %s): retry after %s",
+		e.method,
+		e.url,
+		e.retryAfter,
+	)
+}
+
+// RetryAfter returns the time to wait before sending more requests.
+func (e ErrRateLimited) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// Limit returns the X-RateLimit-Limit header value.
+func (e ErrRateLimited) Limit() int {
+	return e.limit
+}
+
+// Remaining returns the X-RateLimit-Remaining header value.
+func (e ErrRateLimited) Remaining() int {
+	return e.remaining
+}
+
+// Reset returns the X-RateLimit-Reset header value.
+func (e ErrRateLimited) Reset() string {
+	return e.reset
+}
+
+func (e ErrRateLimited) Is(target error) bool {
+	t, ok := target.(ErrRateLimited)
+	return ok && (t == ErrRateLimited{} || t == e)
+}
+
+func (e ErrRateLimited) Unwrap() error {
+	return NewErrResponseStatusCode(e.method, e.url, http.StatusTooManyRequests)
+}
+
+// ErrInvalidConfig is returned by NewClient and NewRateLimiter when a
+// setting is invalid.
+type ErrInvalidConfig struct {
+	field  string
+	reason string
+}
+
+func NewErrInvalidConfig(field, reason string) error {
+	return ErrInvalidConfig{field, reason}
+}
+
+func (e ErrInvalidConfig) Error() string {
+	return fmt.Sprintf(
+		"Invalid client config '%s': %s",
+		e.field,
+		e.reason,
+	)
+}
+
+func (e ErrInvalidConfig) Is(target error) bool {
+	t, ok := target.(ErrInvalidConfig)
+	return ok && (t == ErrInvalidConfig{} || t == e)
+}
+
+// ErrInvalidFilterValue is returned when a List filter value has a type
+// that can't be sent as a query parameter.
+type ErrInvalidFilterValue struct {
+	name      string
+	valueType string
+}
+
+func NewErrInvalidFilterValue(name string, value interface{}) error {
+	return ErrInvalidFilterValue{name, fmt.Sprintf("%T", value)}
+}
+
+func (e ErrInvalidFilterValue) Error() string {
+	return fmt.Sprintf(
+		"Invalid filter value for '%s': unsupported type %s",
+		e.name,
+		e.valueType,
+	)
+}
+
+func (e ErrInvalidFilterValue) Is(target error) bool {
+	t, ok := target.(ErrInvalidFilterValue)
+	return ok && (t == ErrInvalidFilterValue{} || t == e)
+}
+
+// ErrBulkFailed is returned by the bulk operations in ContinueOnError mode
+// when some of the items failed, their results have the errors.
+type ErrBulkFailed struct {
+	failed int
+	total  int
+}
+
+func NewErrBulkFailed(failed, total int) error {
+	return ErrBulkFailed{failed, total}
+}
+
+func (e ErrBulkFailed) Error() string {
+	return fmt.Sprintf(
+		"Bulk operation failed for %d of %d items",
+		e.failed,
+		e.total,
+	)
+}
+
+// Failed returns the number of items that failed.
+func (e ErrBulkFailed) Failed() int {
+	return e.failed
+}
+
+func (e ErrBulkFailed) Is(target error) bool {
+	t, ok := target.(ErrBulkFailed)
+	return ok && (t == ErrBulkFailed{} || t == e)
+}
+
+// ErrBulkSkipped is the result error of the items a FailFast bulk operation
+// didn't process after the first failure.
+type ErrBulkSkipped struct{}
+
+func NewErrBulkSkipped() error {
+	return ErrBulkSkipped{}
+}
+
+func (e ErrBulkSkipped) Error() string {
+	return "Bulk item skipped after a previous failure"
+}
+
+func (e ErrBulkSkipped) Is(target error) bool {
+	_, ok := target.(ErrBulkSkipped)
+	return ok
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// CreateOption configures a single Create call.
+type CreateOption func(*createOptions)
+
+type createOptions struct {
+	idempotencyKey string
+}
+
+// WithIdempotencyKey makes Create safe to retry: the key is sent in the
+// Idempotency-Key header, the request is retried like GET and DELETE ones
+// when the client has a retry policy, and when the server responds 409
+// because a previous attempt created the resource, Create fetches and
+// returns it if it has the same attributes as the one being created.
+func WithIdempotencyKey(key string) CreateOption {
+	return func(o *createOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+func isIdempotentRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost && req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// fetchCreatedResource fetches the resource of a conflicting Create and
+// returns it when it matches the resource sent.
+func (fc Form3Client) fetchCreatedResource(ctx context.Context, resourceName resources.ResourceName, resource resources.Resource) (*resources.DataContainer, bool) {
+	existing, err := fc.Fetch(ctx, resourceName, resource.ID)
+	if err != nil {
+		return nil, false
+	}
+	if !isSameResource(resource, existing.Data) {
+		return nil, false
+	}
+	return existing, true
+}
+
+// isSameResource tells if the existing resource has the organisation and
+// attributes of the resource sent, the attributes the server adds with
+// default values are ignored.
+func isSameResource(sent, existing resources.Resource) bool {
+	if sent.ID != existing.ID || sent.OrganisationID != existing.OrganisationID {
+		return false
+	}
+	sentAttributes, err := normaliseAttributes(sent.Attributes)
+	if err != nil {
+		return false
+	}
+	existingAttributes, err := normaliseAttributes(existing.Attributes)
+	if err != nil {
+		return false
+	}
+	for name, value := range sentAttributes {
+		if !reflect.DeepEqual(value, existingAttributes[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normaliseAttributes converts the attribute values to the types of a
+// decoded JSON document, so []string and []interface{} values compare equal.
+func normaliseAttributes(attributes map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	normalised := map[string]interface{}{}
+	err = json.Unmarshal(data, &normalised)
+	return normalised, err
+}
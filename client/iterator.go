@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const nextLink = "next"
+
+// ListIterator walks all the resources of a List, requesting the pages
+// lazily by following the 'next' link returned by the server.
+type ListIterator struct {
+	ctx          context.Context
+	client       Form3Client
+	resourceName resources.ResourceName
+	pageURL      string
+	page         []resources.Resource
+	index        int
+	current      resources.Resource
+	err          error
+}
+
+// ListAll returns an iterator over every resource matching the filter,
+// starting from the first page. The iteration stops when there is no next
+// page, a request fails or the context is done; stopping early is just a
+// matter of not calling Next again.
+func (fc Form3Client) ListAll(ctx context.Context, resourceName resources.ResourceName, filter map[string]interface{}, pageSize int) *ListIterator {
+	it := &ListIterator{
+		ctx:          ctx,
+		client:       fc,
+		resourceName: resourceName,
+	}
+	parameters, err := buildFilterParameters(filter)
+	if err != nil {
+		it.err = err
+		return it
+	}
+	parameters.Set("page[number]", "0")
+	parameters.Set("page[size]", strconv.Itoa(pageSize))
+	it.pageURL = fc.urlBuilder.DoForResourceWithParameters(resourceName, parameters)
+	return it
+}
+
+// Next moves the iterator to the next resource, requesting a new page when
+// the current one is consumed. It returns false when there are no more
+// resources or an error happened, check Err to tell them apart.
+func (it *ListIterator) Next() bool {
+	for it.index >= len(it.page) {
+		if it.err != nil || it.pageURL == "" {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if err := it.fetchPage(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Resource returns the resource the iterator is on.
+func (it *ListIterator) Resource() resources.Resource {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+func (it *ListIterator) fetchPage() error {
+	data, err := it.client.listURL(it.ctx, it.resourceName, it.pageURL)
+	if err != nil {
+		return err
+	}
+	it.page = data.Data
+	it.index = 0
+
+	link := data.Links[nextLink]
+	if len(data.Data) == 0 || link == "" {
+		it.pageURL = ""
+		return nil
+	}
+	nextURL, err := it.client.urlBuilder.DoForLink(link)
+	if err != nil {
+		return err
+	}
+	if nextURL == it.pageURL {
+		nextURL = ""
+	}
+	it.pageURL = nextURL
+	return nil
+}
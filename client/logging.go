@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	redactedValue   = "[REDACTED]"
+)
+
+// DefaultRedactedAttributes are the account attributes with personal data.
+var DefaultRedactedAttributes = []string{
+	"name",
+	"alternative_names",
+	"iban",
+	"account_number",
+	"secondary_identification",
+}
+
+// Logger is the logging interface of the client, a *slog.Logger satisfies
+// it. The args are key value pairs like in slog.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// LoggingOptions configures the request logging. The bodies are only
+// logged when LogBodies is set, with the values of the RedactedAttributes
+// masked wherever they are in the JSON document, and the filters on them
+// masked in the URL.
+type LoggingOptions struct {
+	LogBodies          bool
+	RedactedAttributes []string
+}
+
+// DefaultLoggingOptions doesn't log the bodies and masks the
+// DefaultRedactedAttributes.
+func DefaultLoggingOptions() LoggingOptions {
+	return LoggingOptions{
+		RedactedAttributes: DefaultRedactedAttributes,
+	}
+}
+
+// WithLogger logs every request attempt with its method, URL, status code,
+// latency and request ID. Failed requests, with an error or a 5XX status
+// code, are logged as errors.
+func WithLogger(logger Logger, options LoggingOptions) Option {
+	redacted := map[string]bool{}
+	for _, attribute := range options.RedactedAttributes {
+		redacted[attribute] = true
+	}
+	l := requestLogger{logger, options.LogBodies, redacted}
+	return WithMiddleware(l.log)
+}
+
+type requestLogger struct {
+	logger    Logger
+	logBodies bool
+	redacted  map[string]bool
+}
+
+func (l requestLogger) log(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+	args := []interface{}{
+		"resource", string(info.ResourceName),
+		"operation", string(info.Operation),
+		"method", req.Method,
+		"url", l.redactURL(req.URL),
+	}
+	if l.logBodies {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "request_body", l.redactBody(body))
+	}
+
+	start := time.Now()
+	resp, err := next(req)
+	args = append(args, "latency", time.Since(start))
+
+	requestID := req.Header.Get(RequestIDHeader)
+	if resp != nil && resp.Header.Get(RequestIDHeader) != "" {
+		requestID = resp.Header.Get(RequestIDHeader)
+	}
+	args = append(args, "request_id", requestID)
+
+	if err != nil {
+		args = append(args, "error", err.Error())
+		l.logger.ErrorContext(req.Context(), "Form3 API request failed", args...)
+		return resp, err
+	}
+	args = append(args, "status", resp.StatusCode)
+	if l.logBodies && resp.Body != nil {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		args = append(args, "response_body", l.redactBody(body))
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		l.logger.ErrorContext(req.Context(), "Form3 API request failed", args...)
+		return resp, nil
+	}
+	l.logger.InfoContext(req.Context(), "Form3 API request", args...)
+	return resp, nil
+}
+
+// redactURL masks the filter query parameters of redacted attributes.
+func (l requestLogger) redactURL(u *url.URL) string {
+	parameters := u.Query()
+	changed := false
+	for name := range parameters {
+		attribute := strings.TrimSuffix(strings.TrimPrefix(name, "filter["), "]")
+		if attribute != name && l.redacted[attribute] {
+			parameters[name] = []string{redactedValue}
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = strings.TrimPrefix(URLBuilder{}.buildQueryParameters(parameters), "?")
+	return redactedURL.String()
+}
+
+// redactBody masks the redacted attributes of a JSON body, bodies that
+// aren't JSON aren't logged as they can't be redacted.
+func (l requestLogger) redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return fmt.Sprintf("[%d bytes, not JSON]", len(body))
+	}
+	redactedBody, err := json.Marshal(l.redactValue(document))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(redactedBody)
+}
+
+func (l requestLogger) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if l.redacted[key] {
+				v[key] = redactedValue
+			} else {
+				v[key] = l.redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = l.redactValue(item)
+		}
+	}
+	return value
+}
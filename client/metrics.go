@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StatusClassError is the status class of the requests without response,
+// like connection errors or cancelled contexts.
+const StatusClassError = "error"
+
+// MetricsRecorder receives the client metrics, it's implemented for a
+// metrics system like in the metrics package for Prometheus.
+type MetricsRecorder interface {
+	// ObserveRequest is called once per client operation request, retries
+	// included, with the status class of the last response like 2xx or 5xx,
+	// or StatusClassError, and the latency of the whole request.
+	ObserveRequest(info RequestInfo, statusClass string, latency time.Duration)
+	// IncRetries is called every time a request is retried.
+	IncRetries(info RequestInfo)
+}
+
+// WithMetrics records the metrics of the requests with the recorder.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(fc *Form3Client) {
+		fc.metrics = recorder
+	}
+}
+
+// IsErrorStatusClass tells if a status class is the one of a failed
+// request, a 4xx or 5xx status code or StatusClassError.
+func IsErrorStatusClass(statusClass string) bool {
+	return statusClass == StatusClassError || statusClass == "4xx" || statusClass == "5xx"
+}
+
+func (fc Form3Client) observeRequest(ctx context.Context, resp *http.Response, start time.Time) {
+	if fc.metrics == nil {
+		return
+	}
+	statusClass := StatusClassError
+	if resp != nil {
+		statusClass = fmt.Sprintf("%dxx", resp.StatusCode/100)
+	}
+	fc.metrics.ObserveRequest(requestInfoFromContext(ctx), statusClass, time.Since(start))
+}
+
+func (fc Form3Client) incRetries(ctx context.Context) {
+	if fc.metrics != nil {
+		fc.metrics.IncRetries(requestInfoFromContext(ctx))
+	}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// Operation is the client operation a request is sent for.
+type Operation string
+
+const (
+	OperationFetch  Operation = "fetch"
+	OperationCreate Operation = "create"
+	OperationList   Operation = "list"
+	OperationDelete Operation = "delete"
+	OperationUpdate Operation = "update"
+)
+
+// RequestInfo describes the client operation of a request, ResourceID is
+// empty for the operations without a resource id like List.
+type RequestInfo struct {
+	ResourceName resources.ResourceName
+	Operation    Operation
+	ResourceID   string
+}
+
+// RequestHandler sends a request, the last handler of the chain is the
+// HTTPClient Do.
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of the requests. It can change the request
+// before calling next, look at the response or error next returns, or
+// short-circuit the chain returning its own response or error without
+// calling next. A returned response must have a body.
+//
+// The middlewares run for every attempt of a request, after the request is
+// signed or gets its bearer token.
+type Middleware func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error)
+
+// WithMiddleware adds middlewares to the client chain. They run in order,
+// so the first one registered is the outermost one.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(fc *Form3Client) {
+		fc.middlewares = append(fc.middlewares, middlewares...)
+	}
+}
+
+type requestInfoKey struct{}
+
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// send sends the request through the middleware chain, and the rate
+// limiter and circuit breaker when the client has them.
+func (fc Form3Client) send(req *http.Request) (*http.Response, error) {
+	info := requestInfoFromContext(req.Context())
+	handler := RequestHandler(fc.httpClient.Do)
+	if fc.circuitBreaker != nil {
+		breaker := fc.circuitBreaker
+		next := handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return breaker.do(req, next)
+		}
+	}
+	if fc.rateLimiter != nil {
+		limiter := fc.rateLimiter
+		next := handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return limiter.do(req, next)
+		}
+	}
+	for i := len(fc.middlewares) - 1; i >= 0; i-- {
+		middleware, next := fc.middlewares[i], handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return middleware(info, req, next)
+		}
+	}
+	return handler(req)
+}
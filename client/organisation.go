@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// FetchOrganisation fetches an organisation unit and returns it with typed
+// attributes.
+func FetchOrganisation(ctx context.Context, c Client, id string) (*resources.OrganisationResource, error) {
+	return NewTypedClient[resources.OrganisationAttributes](c).Fetch(ctx, id)
+}
+
+// CreateOrganisation creates an organisation unit under the organisation
+// of its OrganisationID and returns the unit created by the server.
+func CreateOrganisation(ctx context.Context, c Client, organisation resources.OrganisationResource, options ...CreateOption) (*resources.OrganisationResource, error) {
+	return NewTypedClient[resources.OrganisationAttributes](c).Create(ctx, organisation, options...)
+}
+
+// ListOrganisations returns a page of the organisation units matching the
+// filter.
+func ListOrganisations(ctx context.Context, c Client, filter map[string]interface{}, pageNumber, pageSize int) ([]resources.OrganisationResource, error) {
+	return NewTypedClient[resources.OrganisationAttributes](c).List(ctx, filter, pageNumber, pageSize)
+}
+
+// DeleteOrganisation deletes the organisation unit version.
+func DeleteOrganisation(ctx context.Context, c Client, id string, version int) error {
+	return NewTypedClient[resources.OrganisationAttributes](c).Delete(ctx, id, version)
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// FetchPayment fetches a payment and returns it with typed attributes.
+func FetchPayment(ctx context.Context, c Client, id string) (*resources.PaymentResource, error) {
+	return NewTypedClient[resources.PaymentAttributes](c).Fetch(ctx, id)
+}
+
+// CreatePayment creates a payment from typed attributes and returns the
+// payment created by the server.
+func CreatePayment(ctx context.Context, c Client, payment resources.PaymentResource, options ...CreateOption) (*resources.PaymentResource, error) {
+	return NewTypedClient[resources.PaymentAttributes](c).Create(ctx, payment, options...)
+}
+
+// ListPayments returns a page of the payments matching the filter.
+func ListPayments(ctx context.Context, c Client, filter map[string]interface{}, pageNumber, pageSize int) ([]resources.PaymentResource, error) {
+	return NewTypedClient[resources.PaymentAttributes](c).List(ctx, filter, pageNumber, pageSize)
+}
+
+// FetchPaymentParty fetches the account with the id and returns it as a
+// payment party, to use it as the beneficiary or debtor of a payment.
+func FetchPaymentParty(ctx context.Context, c Client, accountID string) (*resources.PaymentParty, error) {
+	account, err := FetchAccount(ctx, c, accountID)
+	if err != nil {
+		return nil, err
+	}
+	party := resources.NewPaymentParty(*account)
+	return &party, nil
+}
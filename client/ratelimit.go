@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimiter is a token bucket limiting the requests per second of the
+// clients using it, it's safe for concurrent use. The requests wait for a
+// token before being sent, and a 429 response pauses all of them for its
+// Retry-After time.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	paused time.Time
+}
+
+// NewRateLimiter allows rate requests per second on average, with bursts
+// of up to burst requests. It returns an ErrInvalidConfig error when the
+// rate isn't positive or the burst is lower than 1.
+func NewRateLimiter(rate float64, burst int) (*RateLimiter, error) {
+	if rate <= 0 {
+		return nil, NewErrInvalidConfig("rate", "must be greater than 0")
+	}
+	if burst < 1 {
+		return nil, NewErrInvalidConfig("burst", "must be at least 1")
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// WithRateLimiter limits the request attempts with the rate limiter, it
+// can be shared by several clients to limit all of them together.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(fc *Form3Client) {
+		fc.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request can be sent, or returns the context error
+// when it's done before.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancelReservation()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause stops the requests for the duration, on top of the ones waiting
+// for a token.
+func (l *RateLimiter) Pause(duration time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(duration); until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+// reserve takes a token, the bucket can go below zero so the delay is the
+// time until the token is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.paused.Sub(now); pause > delay {
+		delay = pause
+	}
+	return delay
+}
+
+func (l *RateLimiter) cancelReservation() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+func (l *RateLimiter) do(req *http.Request, next RequestHandler) (*http.Response, error) {
+	if err := l.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := next(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			l.Pause(retryAfter)
+		}
+	}
+	return resp, err
+}
+
+// buildRateLimitedError builds the ErrRateLimited error of a 429 response.
+func buildRateLimitedError(method, url string, resp *http.Response) error {
+	retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"))
+	limit, _ := strconv.Atoi(resp.Header.Get(RateLimitLimitHeader))
+	remaining, _ := strconv.Atoi(resp.Header.Get(RateLimitRemainingHeader))
+	return NewErrRateLimited(method, url, retryAfter, limit, remaining, resp.Header.Get(RateLimitResetHeader))
+}
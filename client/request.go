@@ -5,16 +5,31 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/regiluze/form3-account-api-client/resources"
 )
 
-func (fc Form3Client) makeRequest(ctx context.Context, req *http.Request, responseData interface{}) error {
+func (fc Form3Client) makeRequest(ctx context.Context, info RequestInfo, req *http.Request, responseData interface{}) (err error) {
 	req.Header.Set("Accept", DefaultMimeType)
 	req.Header.Set("Content-Type", DefaultMimeType)
+	req.Header.Set(RequestIDHeader, uuid.New().String())
+	if fc.userAgent != "" {
+		req.Header.Set("User-Agent", fc.userAgent)
+	}
+	ctx, cancel := fc.withOperationTimeout(ctx, info.Operation)
+	defer cancel()
+	ctx = withRequestInfo(ctx, info)
+	ctx, span := fc.startSpan(ctx, info, req)
 	cReq := req.WithContext(ctx)
 
-	resp, err := fc.httpClient.Do(cReq)
+	start := time.Now()
+	resp, err := fc.doWithRetry(ctx, cReq)
+	fc.observeRequest(ctx, resp, start)
+	defer func() {
+		endSpan(span, resp, err)
+	}()
 	if err != nil {
 		return err
 	}
@@ -35,6 +50,68 @@ func (fc Form3Client) makeRequest(ctx context.Context, req *http.Request, respon
 	return nil
 }
 
+// do sends a single attempt of the request, authenticating it when the
+// client has a signer or a token source so every retry gets its own date,
+// signature and token. A client has one of them at most, WithHTTPSigner
+// and WithTokenSource replace each other.
+func (fc Form3Client) do(req *http.Request) (*http.Response, error) {
+	if fc.signer != nil {
+		if err := fc.signer.Sign(req); err != nil {
+			return nil, err
+		}
+	}
+	if fc.tokenSource != nil {
+		return fc.doWithToken(req)
+	}
+	return fc.send(req)
+}
+
+// doWithToken sends the request with a bearer token, when the server
+// rejects the token the request is sent once more with a fresh one.
+func (fc Form3Client) doWithToken(req *http.Request) (*http.Response, error) {
+	token, err := fc.tokenSource.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", bearerScheme+token)
+	resp, err := fc.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if !isRequestBodyRewindable(req) {
+		return resp, nil
+	}
+
+	fc.tokenSource.Invalidate(token)
+	token, err = fc.tokenSource.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+	if err := rewindRequestBody(req); err != nil {
+		return resp, nil
+	}
+	discardResponseBody(resp)
+	req.Header.Set("Authorization", bearerScheme+token)
+	return fc.send(req)
+}
+
+func isRequestBodyRewindable(req *http.Request) bool {
+	return req.Body == nil || req.GetBody != nil
+}
+
+// rewindRequestBody resets the request body so the request can be sent again.
+func rewindRequestBody(req *http.Request) error {
+	if req.Body == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
 func (fc Form3Client) isResponseStatusCodeAnError(resp *http.Response, method, url string) error {
 	if resp.StatusCode == http.StatusNotFound {
 		return ErrNotFound{url}
@@ -42,6 +119,16 @@ func (fc Form3Client) isResponseStatusCodeAnError(resp *http.Response, method, u
 	if resp.StatusCode == http.StatusBadRequest {
 		return fc.buildBadRequestError(method, resp)
 	}
+	if resp.StatusCode == http.StatusConflict && method == http.MethodPost {
+		return NewErrConflict(method, url, readErrorMessage(resp))
+	}
+	if resp.StatusCode == http.StatusConflict && (method == http.MethodDelete || method == http.MethodPatch) {
+		return NewErrVersionMismatch(method, url, readErrorMessage(resp))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		discardResponseBody(resp)
+		return buildRateLimitedError(method, url, resp)
+	}
 	if resp.StatusCode > http.StatusBadRequest {
 		return NewErrResponseStatusCode(method, url, resp.StatusCode)
 	}
@@ -59,3 +146,21 @@ func (fc Form3Client) buildBadRequestError(method string, resp *http.Response) e
 	}
 	return NewErrBadRequest(method, errorData)
 }
+
+// readErrorMessage returns the error message of an error response body, or
+// an empty string when the body hasn't got one.
+func readErrorMessage(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+	var errorData resources.BadRequestData
+	if err := json.Unmarshal(body, &errorData); err != nil {
+		return ""
+	}
+	return errorData.ErrorMessage
+}
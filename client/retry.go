@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy defines when and how often a failed request is sent again.
+// A request is retried when the http client returns an error or the
+// response status code is one of RetryableStatusCodes, as long as its
+// method is one of RetryableMethods or it's a Create with an idempotency key.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Zero or negative means a single attempt.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it doubles on each
+	// attempt up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the delays and the Retry-After times, zero or negative
+	// means the DefaultRetryPolicy one.
+	MaxDelay time.Duration
+	// Jitter is the fraction, from 0 to 1, of the delay randomly removed to
+	// spread the retries of concurrent clients.
+	Jitter               float64
+	RetryableStatusCodes []int
+	RetryableMethods     []string
+}
+
+// DefaultRetryPolicy retries idempotent requests up to 3 times on
+// transport errors, 429 and 5XX status codes. POST requests aren't retried
+// unless they are added to RetryableMethods or have an idempotency key.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableMethods: []string{
+			http.MethodGet,
+			http.MethodDelete,
+		},
+	}
+}
+
+// WithRetryPolicy enables retrying failed requests following the policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(fc *Form3Client) {
+		fc.retryPolicy = &policy
+	}
+}
+
+func (fc Form3Client) doWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	policy := fc.retryPolicy
+	if policy == nil {
+		return fc.do(req)
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := fc.do(req)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.isRetryable(req, resp, err) {
+			return resp, err
+		}
+		delay, ok := policy.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if rewindRequestBody(req) != nil {
+			return resp, err
+		}
+		discardResponseBody(resp)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		fc.incRetries(ctx)
+	}
+}
+
+func (p RetryPolicy) isRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if !isRequestBodyRewindable(req) {
+		return false
+	}
+	if !containsString(p.RetryableMethods, req.Method) && !isIdempotentRequest(req) {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen{})
+	}
+	return containsInt(p.RetryableStatusCodes, resp.StatusCode)
+}
+
+// delay returns the delay before the next attempt, the Retry-After time
+// when the response has one. It returns false when Retry-After exceeds
+// MaxDelay, the request isn't retried then.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return retryAfter, retryAfter <= p.maxDelay()
+		}
+	}
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if maxDelay := float64(p.maxDelay()); delay > maxDelay {
+		delay = maxDelay
+	}
+	delay -= delay * p.Jitter * rand.Float64()
+	return time.Duration(delay), true
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return DefaultRetryPolicy().MaxDelay
+	}
+	return p.MaxDelay
+}
+
+// parseRetryAfter reads the Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func discardResponseBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
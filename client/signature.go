@@ -0,0 +1,192 @@
+package client
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	rsaSignatureAlgorithm   = "rsa-sha256"
+	ecdsaSignatureAlgorithm = "ecdsa-sha256"
+	requestTargetHeader     = "(request-target)"
+	digestPrefix            = "SHA-256="
+	signatureScheme         = "Signature "
+)
+
+var signedHeaders = []string{requestTargetHeader, "host", "date", "digest"}
+
+// HTTPSigner signs requests following the HTTP Signatures draft used by the
+// Form3 API, with the (request-target), host, date and digest headers.
+type HTTPSigner struct {
+	keyID      string
+	privateKey crypto.Signer
+	algorithm  string
+	now        func() time.Time
+}
+
+// NewHTTPSigner returns a signer for the key ID registered in Form3, the
+// private key must be an RSA or ECDSA key.
+func NewHTTPSigner(keyID string, privateKey crypto.Signer) (*HTTPSigner, error) {
+	var algorithm string
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = rsaSignatureAlgorithm
+	case *ecdsa.PrivateKey:
+		algorithm = ecdsaSignatureAlgorithm
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+	if keyID == "" {
+		return nil, fmt.Errorf("empty signature key ID")
+	}
+	return &HTTPSigner{
+		keyID:      keyID,
+		privateKey: privateKey,
+		algorithm:  algorithm,
+		now:        time.Now,
+	}, nil
+}
+
+// WithHTTPSigner signs every request sent by the client. It replaces a
+// token source set before, both set the Authorization header.
+func WithHTTPSigner(signer *HTTPSigner) Option {
+	return func(fc *Form3Client) {
+		fc.signer = signer
+		fc.tokenSource = nil
+	}
+}
+
+// Sign sets the Date, Digest and Authorization headers of the request.
+func (s HTTPSigner) Sign(req *http.Request) error {
+	req.Header.Set("Date", s.now().UTC().Format(http.TimeFormat))
+	body, err := readRequestBody(req)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Digest", buildDigest(body))
+
+	hashed := sha256.Sum256([]byte(buildSigningString(req, signedHeaders)))
+	signature, err := s.privateKey.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`%skeyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		signatureScheme,
+		s.keyID,
+		s.algorithm,
+		strings.Join(signedHeaders, " "),
+		base64.StdEncoding.EncodeToString(signature),
+	))
+	return nil
+}
+
+// VerifyHTTPSignature checks the Authorization signature and the Digest of
+// a request signed by HTTPSigner, it's meant for tests and fake servers.
+func VerifyHTTPSignature(req *http.Request, publicKey crypto.PublicKey) error {
+	parameters, err := parseSignatureHeader(req.Header.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return err
+	}
+	if req.Header.Get("Digest") != buildDigest(body) {
+		return fmt.Errorf("request digest doesn't match the body")
+	}
+	signature, err := base64.StdEncoding.DecodeString(parameters["signature"])
+	if err != nil {
+		return err
+	}
+	headers := strings.Fields(parameters["headers"])
+	hashed := sha256.Sum256([]byte(buildSigningString(req, headers)))
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hashed[:], signature) {
+			err = fmt.Errorf("invalid ecdsa signature")
+		}
+	default:
+		err = fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return err
+}
+
+func buildSigningString(req *http.Request, headers []string) string {
+	lines := []string{}
+	for _, header := range headers {
+		var value string
+		switch header {
+		case requestTargetHeader:
+			value = fmt.Sprintf("%s %s", strings.ToLower(req.Method), req.URL.RequestURI())
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		default:
+			value = req.Header.Get(header)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", header, value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func buildDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return digestPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readRequestBody returns the request body without consuming it.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return []byte{}, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+func parseSignatureHeader(header string) (map[string]string, error) {
+	if !strings.HasPrefix(header, signatureScheme) {
+		return nil, fmt.Errorf("missing signature in authorization header")
+	}
+	parameters := map[string]string{}
+	for _, parameter := range strings.Split(strings.TrimPrefix(header, signatureScheme), ",") {
+		parts := strings.SplitN(strings.TrimSpace(parameter), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed signature parameter %q", parameter)
+		}
+		parameters[parts[0]] = strings.Trim(parts[1], `"`)
+	}
+	return parameters, nil
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTokenExpiryMargin   = 30 * time.Second
+	DefaultTokenRequestTimeout = 10 * time.Second
+	bearerScheme               = "Bearer "
+)
+
+// TokenSource provides the bearer tokens sent in the Authorization header.
+// Invalidate is called with a token rejected by the server, so the next
+// Token call returns a fresh one.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+	Invalidate(token string)
+}
+
+// ClientCredentials configures the OAuth2 client credentials grant.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// ExpiryMargin is how long before its expiry a token is refreshed,
+	// DefaultTokenExpiryMargin when zero. It's at most half of the token
+	// lifetime, so short-lived tokens are reused too.
+	ExpiryMargin time.Duration
+	// RequestTimeout bounds every token request, DefaultTokenRequestTimeout
+	// when zero.
+	RequestTimeout time.Duration
+}
+
+// ClientCredentialsTokenSource fetches tokens from the token endpoint and
+// caches them until shortly before they expire. It's safe for concurrent
+// use, there is only one token request in flight at a time.
+type ClientCredentialsTokenSource struct {
+	credentials ClientCredentials
+	httpClient  HTTPClient
+	now         func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	refresh *tokenRefresh
+}
+
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewClientCredentialsTokenSource(credentials ClientCredentials, httpClient HTTPClient) *ClientCredentialsTokenSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if credentials.ExpiryMargin == 0 {
+		credentials.ExpiryMargin = DefaultTokenExpiryMargin
+	}
+	if credentials.RequestTimeout == 0 {
+		credentials.RequestTimeout = DefaultTokenRequestTimeout
+	}
+	return &ClientCredentialsTokenSource{
+		credentials: credentials,
+		httpClient:  httpClient,
+		now:         time.Now,
+	}
+}
+
+// WithTokenSource sends a bearer token from the source in every request.
+// A request rejected with a 401 status code is sent once more with a fresh
+// token. It replaces an HTTP signer set before, both set the Authorization
+// header.
+func WithTokenSource(source TokenSource) Option {
+	return func(fc *Form3Client) {
+		fc.tokenSource = source
+		fc.signer = nil
+	}
+}
+
+func (s *ClientCredentialsTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	if s.token != "" && (s.expiry.IsZero() || s.now().Before(s.expiry)) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	if s.refresh == nil {
+		s.refresh = &tokenRefresh{done: make(chan struct{})}
+		go s.fetchToken(s.refresh)
+	}
+	refresh := s.refresh
+	s.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (s *ClientCredentialsTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// fetchToken isn't bound to the context of the caller that started it,
+// other callers may be waiting for the same token, the token request has
+// its own timeout instead.
+func (s *ClientCredentialsTokenSource) fetchToken(refresh *tokenRefresh) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.credentials.RequestTimeout)
+	defer cancel()
+	token, expiresIn, err := s.requestToken(ctx)
+
+	s.mu.Lock()
+	if err == nil {
+		s.token = token
+		s.expiry = time.Time{}
+		if expiresIn > 0 {
+			margin := s.credentials.ExpiryMargin
+			if margin > expiresIn/2 {
+				margin = expiresIn / 2
+			}
+			s.expiry = s.now().Add(expiresIn - margin)
+		}
+	}
+	s.refresh = nil
+	s.mu.Unlock()
+
+	refresh.token = token
+	refresh.err = err
+	close(refresh.done)
+}
+
+func (s *ClientCredentialsTokenSource) requestToken(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.credentials.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.credentials.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.credentials.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.SetBasicAuth(url.QueryEscape(s.credentials.ClientID), url.QueryEscape(s.credentials.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, NewErrResponseStatusCode(req.Method, req.URL.String(), resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+	var tokenData tokenResponse
+	if err := json.Unmarshal(body, &tokenData); err != nil {
+		return "", 0, err
+	}
+	if tokenData.AccessToken == "" {
+		return "", 0, fmt.Errorf("empty access token from %s", req.URL.String())
+	}
+	return tokenData.AccessToken, time.Duration(tokenData.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/regiluze/form3-account-api-client/client"
+
+// WithTracing creates an OpenTelemetry span for every client operation
+// request, retries included, and propagates the trace context in the
+// request headers. The propagator defaults to the W3C traceparent one
+// when it's nil.
+func WithTracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(fc *Form3Client) {
+		if propagator == nil {
+			propagator = propagation.TraceContext{}
+		}
+		fc.tracer = provider.Tracer(tracerName)
+		fc.propagator = propagator
+	}
+}
+
+// startSpan starts the span of the request and injects its context in the
+// request headers, the span has the resource, id and page attributes.
+func (fc Form3Client) startSpan(ctx context.Context, info RequestInfo, req *http.Request) (context.Context, trace.Span) {
+	if fc.tracer == nil {
+		return ctx, nil
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("form3.resource", string(info.ResourceName)),
+		attribute.String("form3.operation", string(info.Operation)),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.String()),
+	}
+	if info.ResourceID != "" {
+		attributes = append(attributes, attribute.String("form3.resource_id", info.ResourceID))
+	}
+	query := req.URL.Query()
+	for name, key := range map[string]string{"page[number]": "form3.page_number", "page[size]": "form3.page_size"} {
+		if value, err := strconv.Atoi(query.Get(name)); err == nil {
+			attributes = append(attributes, attribute.Int(key, value))
+		}
+	}
+	ctx, span := fc.tracer.Start(
+		ctx,
+		fmt.Sprintf("%s %s", info.Operation, info.ResourceName),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	fc.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return ctx, span
+}
+
+// endSpan records the response status code and the error of the request,
+// if any, and ends the span.
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	if span == nil {
+		return
+	}
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
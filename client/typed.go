@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// TypedClient requests the resources of type T with typed attributes on
+// top of a Client, for instance TypedClient[resources.AccountAttributes].
+// A new resource type only needs its attributes struct implementing
+// resources.ResourceAttributes.
+type TypedClient[T resources.ResourceAttributes] struct {
+	client       Client
+	resourceName resources.ResourceName
+}
+
+// NewTypedClient returns a TypedClient of T, registering the endpoint of T
+// the first time. It's cheap, the helpers build one per request.
+func NewTypedClient[T resources.ResourceAttributes](c Client) TypedClient[T] {
+	var attributes T
+	registerMissingEndpoint(attributes.ResourceName(), attributes.Endpoint())
+	return TypedClient[T]{
+		client:       c,
+		resourceName: attributes.ResourceName(),
+	}
+}
+
+// Fetch fetches a resource and returns it with typed attributes.
+func (tc TypedClient[T]) Fetch(ctx context.Context, id string) (*resources.TypedResource[T], error) {
+	resp, err := tc.client.Fetch(ctx, tc.resourceName, id)
+	if err != nil {
+		return nil, err
+	}
+	return newTypedResource[T](resp.Data)
+}
+
+// Create creates a resource from typed attributes and returns the resource
+// created by the server.
+func (tc TypedClient[T]) Create(ctx context.Context, resource resources.TypedResource[T], options ...CreateOption) (*resources.TypedResource[T], error) {
+	data, err := resource.Resource()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tc.client.Create(ctx, tc.resourceName, data, options...)
+	if err != nil {
+		return nil, err
+	}
+	return newTypedResource[T](resp.Data)
+}
+
+// List returns a page of the resources matching the filter.
+func (tc TypedClient[T]) List(ctx context.Context, filter map[string]interface{}, pageNumber, pageSize int) ([]resources.TypedResource[T], error) {
+	resp, err := tc.client.List(ctx, tc.resourceName, filter, pageNumber, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	page := make([]resources.TypedResource[T], 0, len(resp.Data))
+	for _, data := range resp.Data {
+		resource, err := resources.NewTypedResource[T](data)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, resource)
+	}
+	return page, nil
+}
+
+// Update updates the resource attributes, the resource version must be the
+// current one.
+func (tc TypedClient[T]) Update(ctx context.Context, resource resources.TypedResource[T]) (*resources.TypedResource[T], error) {
+	data, err := resource.Resource()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tc.client.Update(ctx, tc.resourceName, data)
+	if err != nil {
+		return nil, err
+	}
+	return newTypedResource[T](resp.Data)
+}
+
+// Delete deletes the resource version.
+func (tc TypedClient[T]) Delete(ctx context.Context, id string, version int) error {
+	return tc.client.Delete(ctx, tc.resourceName, id, version)
+}
+
+func newTypedResource[T resources.ResourceAttributes](data resources.Resource) (*resources.TypedResource[T], error) {
+	resource, err := resources.NewTypedResource[T](data)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// stringsFlag collects the values of a flag that can be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func newCreateCommand(flags *flag.FlagSet, options *commonOptions) command {
+	var (
+		file           = flags.String("file", "", "JSON or YAML file with the account resource, '-' for stdin")
+		id             = flags.String("id", "", "account id, a random uuid by default")
+		organisationID = flags.String("organisation-id", "", "organisation id")
+		attributes     resources.AccountAttributes
+		names          stringsFlag
+		extra          stringsFlag
+	)
+	flags.StringVar(&attributes.Country, "country", "", "country attribute")
+	flags.StringVar(&attributes.BaseCurrency, "base-currency", "", "base_currency attribute")
+	flags.StringVar(&attributes.BankID, "bank-id", "", "bank_id attribute")
+	flags.StringVar(&attributes.BankIDCode, "bank-id-code", "", "bank_id_code attribute")
+	flags.StringVar(&attributes.Bic, "bic", "", "bic attribute")
+	flags.StringVar(&attributes.AccountNumber, "account-number", "", "account_number attribute")
+	flags.StringVar(&attributes.Iban, "iban", "", "iban attribute")
+	flags.StringVar(&attributes.AccountClassification, "account-classification", "", "account_classification attribute")
+	flags.Var(&names, "name", "name attribute line, can be repeated")
+	flags.Var(&extra, "attribute", "any other attribute as name=value, can be repeated")
+
+	return func(ctx context.Context, apiClient client.Client, args []string, out io.Writer) error {
+		var resource resources.Resource
+		if *file != "" {
+			var err error
+			if resource, err = readResourceFile(*file); err != nil {
+				return err
+			}
+		} else {
+			attributes.Name = names
+			if len(extra) > 0 {
+				attributes.Extra = map[string]interface{}{}
+				for _, attribute := range extra {
+					parts := strings.SplitN(attribute, "=", 2)
+					if len(parts) != 2 {
+						return fmt.Errorf("invalid attribute %q, expected name=value", attribute)
+					}
+					attributes.Extra[parts[0]] = parts[1]
+				}
+			}
+			account := resources.AccountResource{
+				ID:             *id,
+				OrganisationID: *organisationID,
+				Attributes:     attributes,
+			}
+			var err error
+			if resource, err = account.Resource(); err != nil {
+				return err
+			}
+		}
+		if resource.ID == "" {
+			resource.ID = uuid.New().String()
+		}
+
+		resp, err := apiClient.Create(ctx, resources.Account, resource)
+		if err != nil {
+			return err
+		}
+		return writeResource(out, options.output, resp.Data)
+	}
+}
+
+func newFetchCommand(flags *flag.FlagSet, options *commonOptions) command {
+	id := flags.String("id", "", "account id, it can be the first argument too")
+
+	return func(ctx context.Context, apiClient client.Client, args []string, out io.Writer) error {
+		accountID, err := idFromFlagOrArgs(*id, args)
+		if err != nil {
+			return err
+		}
+		resp, err := apiClient.Fetch(ctx, resources.Account, accountID)
+		if err != nil {
+			return err
+		}
+		return writeResource(out, options.output, resp.Data)
+	}
+}
+
+func newListCommand(flags *flag.FlagSet, options *commonOptions) command {
+	var (
+		pageNumber = flags.Int("page-number", 0, "page number")
+		pageSize   = flags.Int("page-size", 100, "page size")
+		all        = flags.Bool("all", false, "list every page")
+		filters    stringsFlag
+	)
+	flags.Var(&filters, "filter", "attribute filter as name=value1,value2, can be repeated")
+
+	return func(ctx context.Context, apiClient client.Client, args []string, out io.Writer) error {
+		filter := map[string]interface{}{}
+		for _, f := range filters {
+			parts := strings.SplitN(f, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("invalid filter %q, expected name=value", f)
+			}
+			filter[parts[0]] = strings.Split(parts[1], ",")
+		}
+
+		if !*all {
+			resp, err := apiClient.List(ctx, resources.Account, filter, *pageNumber, *pageSize)
+			if err != nil {
+				return err
+			}
+			return writeResources(out, options.output, resp.Data)
+		}
+		form3Client, ok := apiClient.(*client.Form3Client)
+		if !ok {
+			return errors.New("listing every page is not supported by this client")
+		}
+		it := form3Client.ListAll(ctx, resources.Account, filter, *pageSize)
+		accounts := []resources.Resource{}
+		for it.Next() {
+			accounts = append(accounts, it.Resource())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return writeResources(out, options.output, accounts)
+	}
+}
+
+func newDeleteCommand(flags *flag.FlagSet, options *commonOptions) command {
+	var (
+		id      = flags.String("id", "", "account id, it can be the first argument too")
+		version = flags.Int("version", 0, "account version")
+	)
+
+	return func(ctx context.Context, apiClient client.Client, args []string, out io.Writer) error {
+		accountID, err := idFromFlagOrArgs(*id, args)
+		if err != nil {
+			return err
+		}
+		return apiClient.Delete(ctx, resources.Account, accountID, *version)
+	}
+}
+
+func idFromFlagOrArgs(id string, args []string) (string, error) {
+	if id != "" {
+		return id, nil
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return "", errors.New("expected one account id")
+}
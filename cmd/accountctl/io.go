@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable  = "table"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+var tableAttributes = []string{"country", "base_currency", "bank_id", "bic", "account_number", "iban"}
+
+func isOutputFormat(format string) bool {
+	return format == outputTable || format == outputJSON || format == outputNDJSON
+}
+
+// readResourceFile reads an account resource from a JSON or YAML file, both
+// the resource itself and a JSON:API document with a data member are valid.
+func readResourceFile(path string) (resources.Resource, error) {
+	var resource resources.Resource
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return resource, err
+	}
+
+	// YAML is a superset of JSON, so one decoder reads both formats
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return resource, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if data, ok := document["data"]; ok {
+		dataMap, ok := data.(map[string]interface{})
+		if !ok {
+			return resource, fmt.Errorf("reading %s: data must be an object", path)
+		}
+		document = dataMap
+	}
+	dataB, err := json.Marshal(document)
+	if err != nil {
+		return resource, err
+	}
+	if err := json.Unmarshal(dataB, &resource); err != nil {
+		return resource, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if resource.ResourceType == "" {
+		resource.ResourceType = resources.AccountType
+	}
+	return resource, nil
+}
+
+// writeResource writes a single account, a JSON object with the json
+// output format.
+func writeResource(out io.Writer, format string, account resources.Resource) error {
+	if format == outputJSON {
+		return writeJSON(out, account)
+	}
+	return writeResources(out, format, []resources.Resource{account})
+}
+
+// writeResources writes a list of accounts, always a JSON array with the
+// json output format so its shape doesn't depend on the accounts count.
+func writeResources(out io.Writer, format string, accounts []resources.Resource) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(out, accounts)
+	case outputNDJSON:
+		encoder := json.NewEncoder(out)
+		for _, account := range accounts {
+			if err := encoder.Encode(account); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := append([]string{"ID", "ORGANISATION_ID", "VERSION"}, tableAttributes...)
+	fmt.Fprintln(writer, strings.ToUpper(strings.Join(header, "\t")))
+	for _, account := range accounts {
+		row := []string{account.ID, account.OrganisationID, fmt.Sprint(account.Version)}
+		for _, attribute := range tableAttributes {
+			value, ok := account.Attributes[attribute]
+			if !ok {
+				value = "-"
+			}
+			row = append(row, fmt.Sprint(value))
+		}
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,101 @@
+// Command accountctl creates, fetches, lists and deletes accounts of the
+// Form3 account API.
+//
+// Usage:
+//
+//	accountctl <command> [flags]
+//
+// The commands are create, fetch, list and delete, run
+// 'accountctl <command> -h' to see their flags. The API base URL is read from
+// the FORM3_API_BASE_URL environment variable unless -base-url is set, and
+// the credentials from the FORM3_API_CLIENT_ID and FORM3_API_CLIENT_SECRET or
+// FORM3_API_SIGNING_KEY_ID and FORM3_API_SIGNING_KEY_FILE ones.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/regiluze/form3-account-api-client/client"
+)
+
+const (
+	baseURLEnvVariable = client.EnvBaseURL
+	defaultTimeout     = 30 * time.Second
+	usage              = `Usage: accountctl <command> [flags]
+
+Commands:
+  create   create an account from a JSON/YAML file or flags
+  fetch    fetch an account by id
+  list     list accounts, one page or all of them
+  delete   delete an account by id and version
+`
+)
+
+type command func(ctx context.Context, apiClient client.Client, args []string, out io.Writer) error
+
+var commands = map[string]func(flags *flag.FlagSet, options *commonOptions) command{
+	"create": newCreateCommand,
+	"fetch":  newFetchCommand,
+	"list":   newListCommand,
+	"delete": newDeleteCommand,
+}
+
+// commonOptions are the flags shared by every command.
+type commonOptions struct {
+	baseURL string
+	output  string
+	timeout time.Duration
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, out, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(errOut, usage)
+		return 2
+	}
+	newCommand, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(errOut, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	options := &commonOptions{}
+	flags.StringVar(&options.baseURL, "base-url", os.Getenv(baseURLEnvVariable), "API base URL, "+baseURLEnvVariable+" by default")
+	flags.StringVar(&options.output, "output", outputTable, "output format: table, json or ndjson")
+	flags.DurationVar(&options.timeout, "timeout", defaultTimeout, "request timeout")
+	cmd := newCommand(flags, options)
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if options.baseURL == "" {
+		fmt.Fprintf(errOut, "missing API base URL, set -base-url or %s\n", baseURLEnvVariable)
+		return 2
+	}
+	if !isOutputFormat(options.output) {
+		fmt.Fprintf(errOut, "unknown output format %q\n", options.output)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+	apiClient, err := client.NewClient(client.WithEnvironment(), client.WithBaseURL(options.baseURL))
+	if err != nil {
+		fmt.Fprintf(errOut, "%s\n", err)
+		return 2
+	}
+	if err := cmd(ctx, apiClient, flags.Args(), out); err != nil {
+		fmt.Fprintf(errOut, "%s: %s\n", args[0], err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,284 @@
+// +build unit
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	"github.com/regiluze/form3-account-api-client/fakeapi"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const (
+	id             = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	id2            = "8f1a1b5e-3b4c-4d7e-9f0a-1b2c3d4e5f60"
+	organisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+)
+
+const accountYAML = `
+data:
+  type: accounts
+  id: ad27e265-9605-4b4b-a0e5-3003ea9cc4dc
+  organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+  attributes:
+    country: GB
+    base_currency: GBP
+    bank_id: "400300"
+    bic: NWBKGB22
+    name:
+      - Samantha Holder
+`
+
+var _ = Describe("accountctl", func() {
+	var (
+		fakeServer *fakeapi.Server
+		dir        string
+	)
+
+	BeforeEach(func() {
+		fakeServer = fakeapi.NewServer()
+		var err error
+		dir, err = ioutil.TempDir("", "accountctl")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+		os.RemoveAll(dir)
+	})
+
+	// runCommand runs accountctl against the fake API and returns the exit
+	// code and what it wrote to stdout and stderr.
+	runCommand := func(args ...string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		if len(args) > 0 {
+			args = append([]string{args[0], "-base-url", fakeServer.BaseURL()}, args[1:]...)
+		}
+		code := run(args, &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	createAccount := func(accountID string, output string) (int, string, string) {
+		return runCommand(
+			"create",
+			"-output", output,
+			"-id", accountID,
+			"-organisation-id", organisationID,
+			"-country", "GB",
+			"-base-currency", "GBP",
+			"-bank-id", "400300",
+			"-bic", "NWBKGB22",
+			"-name", "Samantha Holder",
+		)
+	}
+
+	decodeAccount := func(output string) resources.Resource {
+		var account resources.Resource
+		Expect(json.Unmarshal([]byte(output), &account)).To(Succeed())
+		return account
+	}
+
+	Context("Create", func() {
+		It("creates an account from flags and writes it as JSON", func() {
+			code, out, errOut := createAccount(id, outputJSON)
+
+			Expect(code).To(Equal(0))
+			Expect(errOut).To(BeEmpty())
+			account := decodeAccount(out)
+			Expect(account.ID).To(Equal(id))
+			Expect(account.Attributes["bic"]).To(Equal("NWBKGB22"))
+		})
+		It("creates an account and writes it as a table", func() {
+			code, out, _ := createAccount(id, outputTable)
+
+			Expect(code).To(Equal(0))
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(HavePrefix("ID"))
+			Expect(strings.Fields(lines[1])).To(Equal([]string{id, organisationID, "0", "GB", "GBP", "400300", "NWBKGB22", "-", "-"}))
+		})
+		It("creates an account and writes it as NDJSON", func() {
+			code, out, _ := createAccount(id, outputNDJSON)
+
+			Expect(code).To(Equal(0))
+			Expect(strings.Count(out, "\n")).To(Equal(1))
+			Expect(decodeAccount(out).ID).To(Equal(id))
+		})
+		It("creates an account from a YAML file", func() {
+			path := filepath.Join(dir, "account.yaml")
+			Expect(ioutil.WriteFile(path, []byte(accountYAML), 0600)).To(Succeed())
+
+			code, out, _ := runCommand("create", "-output", outputJSON, "-file", path)
+
+			Expect(code).To(Equal(0))
+			account := decodeAccount(out)
+			Expect(account.ID).To(Equal(id))
+			Expect(account.ResourceType).To(Equal(resources.AccountType))
+			Expect(account.Attributes["name"]).To(Equal([]interface{}{"Samantha Holder"}))
+		})
+		It("exits with 1 when the API rejects the account", func() {
+			code, out, errOut := runCommand("create", "-id", id, "-organisation-id", organisationID)
+
+			Expect(code).To(Equal(1))
+			Expect(out).To(BeEmpty())
+			Expect(errOut).To(ContainSubstring("country in body is required"))
+		})
+		It("exits with 1 when the file can't be read", func() {
+			code, _, errOut := runCommand("create", "-file", filepath.Join(dir, "missing.yaml"))
+
+			Expect(code).To(Equal(1))
+			Expect(errOut).To(HavePrefix("create: "))
+		})
+	})
+	Context("Fetch", func() {
+		BeforeEach(func() {
+			code, _, _ := createAccount(id, outputJSON)
+			Expect(code).To(Equal(0))
+		})
+
+		It("fetches an account and writes it as JSON", func() {
+			code, out, _ := runCommand("fetch", "-output", outputJSON, id)
+
+			Expect(code).To(Equal(0))
+			Expect(decodeAccount(out).ID).To(Equal(id))
+		})
+		It("fetches an account and writes it as a table", func() {
+			code, out, _ := runCommand("fetch", "-id", id)
+
+			Expect(code).To(Equal(0))
+			Expect(strings.Split(strings.TrimSpace(out), "\n")).To(HaveLen(2))
+			Expect(out).To(ContainSubstring(id))
+		})
+		It("fetches an account and writes it as NDJSON", func() {
+			code, out, _ := runCommand("fetch", "-output", outputNDJSON, id)
+
+			Expect(code).To(Equal(0))
+			Expect(strings.Count(out, "\n")).To(Equal(1))
+			Expect(decodeAccount(out).ID).To(Equal(id))
+		})
+		It("exits with 1 when the account doesn't exist", func() {
+			code, _, errOut := runCommand("fetch", id2)
+
+			Expect(code).To(Equal(1))
+			Expect(errOut).To(HavePrefix("fetch: "))
+		})
+		It("exits with 1 without an account id", func() {
+			code, _, errOut := runCommand("fetch")
+
+			Expect(code).To(Equal(1))
+			Expect(errOut).To(Equal("fetch: expected one account id\n"))
+		})
+	})
+	Context("List", func() {
+		BeforeEach(func() {
+			for _, accountID := range []string{id, id2} {
+				code, _, _ := createAccount(accountID, outputJSON)
+				Expect(code).To(Equal(0))
+			}
+		})
+
+		It("lists a page of accounts and writes them as a JSON array", func() {
+			code, out, _ := runCommand("list", "-output", outputJSON)
+
+			Expect(code).To(Equal(0))
+			var accounts []resources.Resource
+			Expect(json.Unmarshal([]byte(out), &accounts)).To(Succeed())
+			Expect(accounts).To(HaveLen(2))
+		})
+		It("writes a JSON array when exactly one account is listed", func() {
+			code, out, _ := runCommand("list", "-output", outputJSON, "-page-size", "1")
+
+			Expect(code).To(Equal(0))
+			var accounts []resources.Resource
+			Expect(json.Unmarshal([]byte(out), &accounts)).To(Succeed())
+			Expect(accounts).To(HaveLen(1))
+		})
+		It("lists a page of accounts and writes them as a table", func() {
+			code, out, _ := runCommand("list", "-page-size", "1")
+
+			Expect(code).To(Equal(0))
+			Expect(strings.Split(strings.TrimSpace(out), "\n")).To(HaveLen(2))
+		})
+		It("lists every page of accounts and writes them as NDJSON", func() {
+			code, out, _ := runCommand("list", "-output", outputNDJSON, "-all", "-page-size", "1")
+
+			Expect(code).To(Equal(0))
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect([]string{decodeAccount(lines[0]).ID, decodeAccount(lines[1]).ID}).To(ConsistOf(id, id2))
+		})
+		It("exits with 1 when a filter is malformed", func() {
+			code, _, errOut := runCommand("list", "-filter", "country")
+
+			Expect(code).To(Equal(1))
+			Expect(errOut).To(Equal("list: invalid filter \"country\", expected name=value\n"))
+		})
+	})
+	Context("Delete", func() {
+		BeforeEach(func() {
+			code, _, _ := createAccount(id, outputJSON)
+			Expect(code).To(Equal(0))
+		})
+
+		It("deletes an account version", func() {
+			code, out, _ := runCommand("delete", "-version", "0", id)
+
+			Expect(code).To(Equal(0))
+			Expect(out).To(BeEmpty())
+			code, _, _ = runCommand("fetch", id)
+			Expect(code).To(Equal(1))
+		})
+		It("exits with 1 when the version doesn't match", func() {
+			code, _, errOut := runCommand("delete", "-version", "3", id)
+
+			Expect(code).To(Equal(1))
+			Expect(errOut).To(HavePrefix("delete: "))
+		})
+	})
+	Context("Usage errors", func() {
+		It("exits with 2 without a command", func() {
+			var out, errOut bytes.Buffer
+
+			Expect(run(nil, &out, &errOut)).To(Equal(2))
+			Expect(errOut.String()).To(Equal(usage))
+		})
+		It("exits with 2 with an unknown command", func() {
+			var out, errOut bytes.Buffer
+
+			Expect(run([]string{"update"}, &out, &errOut)).To(Equal(2))
+			Expect(errOut.String()).To(HavePrefix("unknown command \"update\""))
+		})
+		It("exits with 2 with an unknown flag", func() {
+			code, _, _ := runCommand("fetch", "-unknown", id)
+
+			Expect(code).To(Equal(2))
+		})
+		It("exits with 2 with an unknown output format", func() {
+			code, _, errOut := runCommand("fetch", "-output", "xml", id)
+
+			Expect(code).To(Equal(2))
+			Expect(errOut).To(Equal("unknown output format \"xml\"\n"))
+		})
+		It("exits with 2 without the API base URL", func() {
+			baseURL, isSet := os.LookupEnv(baseURLEnvVariable)
+			os.Unsetenv(baseURLEnvVariable)
+			defer func() {
+				if isSet {
+					os.Setenv(baseURLEnvVariable, baseURL)
+				}
+			}()
+			var out, errOut bytes.Buffer
+
+			Expect(run([]string{"fetch", id}, &out, &errOut)).To(Equal(2))
+			Expect(errOut.String()).To(HavePrefix("missing API base URL"))
+		})
+	})
+})
@@ -0,0 +1,271 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+func (h handler) createAccount(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var data resources.DataContainer
+	if err := json.Unmarshal(body, &data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if message := validateAccount(data.Data); message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	if h.store.findAccount(data.Data.ID) >= 0 {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+	account := data.Data
+	now := h.store.now().UTC().Format(time.RFC3339Nano)
+	account.Version = 0
+	account.CreatedOn = now
+	account.ModifiedOn = now
+	h.store.accounts = append(h.store.accounts, account)
+
+	writeData(w, http.StatusCreated, resources.DataContainer{
+		Data:  account,
+		Links: map[string]string{"self": fmt.Sprintf("%s/%s", accountsPath, account.ID)},
+	})
+}
+
+func (h handler) fetchAccount(w http.ResponseWriter, id string) {
+	if !uuidRegexp.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	index := h.store.findAccount(id)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	writeData(w, http.StatusOK, resources.DataContainer{
+		Data:  h.store.accounts[index],
+		Links: map[string]string{"self": fmt.Sprintf("%s/%s", accountsPath, id)},
+	})
+}
+
+func (h handler) listAccounts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pageNumber, err := parsePageParameter(query, "page[number]", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pageSize, err := parsePageParameter(query, "page[size]", defaultPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if pageNumber < 0 || pageSize < 0 {
+		// The real API fails with an internal error on negative pages.
+		writeError(w, http.StatusInternalServerError, "invalid page parameters")
+		return
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	filter := parseFilter(query)
+
+	h.store.mu.Lock()
+	matching := []resources.Resource{}
+	for _, account := range h.store.accounts {
+		if matchesFilter(account, filter) {
+			matching = append(matching, account)
+		}
+	}
+	h.store.mu.Unlock()
+
+	// The page number is checked before multiplying, so huge page numbers
+	// don't overflow.
+	start, end := len(matching), len(matching)
+	if pageSize > 0 && pageNumber <= len(matching)/pageSize {
+		start = pageNumber * pageSize
+		if start+pageSize < end {
+			end = start + pageSize
+		}
+	}
+	writeData(w, http.StatusOK, resources.ListDataContainer{
+		Data:  matching[start:end],
+		Links: buildPageLinks(query, pageNumber, pageSize, len(matching)),
+		Meta:  map[string]interface{}{"count": len(matching)},
+	})
+}
+
+// updateAccount replaces the attributes sent in the request, the version
+// must be the current one and it's increased after the update.
+func (h handler) updateAccount(w http.ResponseWriter, r *http.Request, id string) {
+	if !uuidRegexp.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var data resources.DataContainer
+	if err := json.Unmarshal(body, &data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	index := h.store.findAccount(id)
+	if index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	account := h.store.accounts[index]
+	if account.Version != data.Data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	attributes := map[string]interface{}{}
+	for name, value := range account.Attributes {
+		attributes[name] = value
+	}
+	for name, value := range data.Data.Attributes {
+		attributes[name] = value
+	}
+	account.Attributes = attributes
+	if message := validateAccount(account); message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+	account.Version++
+	account.ModifiedOn = h.store.now().UTC().Format(time.RFC3339Nano)
+	h.store.accounts[index] = account
+
+	writeData(w, http.StatusOK, resources.DataContainer{
+		Data:  account,
+		Links: map[string]string{"self": fmt.Sprintf("%s/%s", accountsPath, id)},
+	})
+}
+
+func (h handler) deleteAccount(w http.ResponseWriter, r *http.Request, id string) {
+	if !uuidRegexp.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	index := h.store.findAccount(id)
+	if index < 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if h.store.accounts[index].Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	h.store.accounts = append(h.store.accounts[:index], h.store.accounts[index+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *store) findAccount(id string) int {
+	for i, account := range s.accounts {
+		if account.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePageParameter(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return number, nil
+}
+
+// parseFilter reads the filter[name]=value1,value2 query parameters.
+func parseFilter(query url.Values) map[string][]string {
+	filter := map[string][]string{}
+	for name := range query {
+		if strings.HasPrefix(name, "filter[") && strings.HasSuffix(name, "]") {
+			attribute := strings.TrimSuffix(strings.TrimPrefix(name, "filter["), "]")
+			filter[attribute] = strings.Split(query.Get(name), ",")
+		}
+	}
+	return filter
+}
+
+func matchesFilter(account resources.Resource, filter map[string][]string) bool {
+	for attribute, values := range filter {
+		value, ok := account.Attributes[attribute]
+		if !ok {
+			return false
+		}
+		matches := false
+		for _, filterValue := range values {
+			if fmt.Sprint(value) == filterValue {
+				matches = true
+			}
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+func buildPageLinks(query url.Values, pageNumber, pageSize, count int) map[string]string {
+	pageLink := func(number int) string {
+		parameters := url.Values{}
+		for name, values := range query {
+			parameters[name] = values
+		}
+		parameters.Set("page[number]", strconv.Itoa(number))
+		parameters.Set("page[size]", strconv.Itoa(pageSize))
+		return fmt.Sprintf("%s?%s", accountsPath, parameters.Encode())
+	}
+	lastPage := 0
+	if pageSize > 0 && count > 0 {
+		lastPage = (count - 1) / pageSize
+	}
+	links := map[string]string{
+		"self":  pageLink(pageNumber),
+		"first": pageLink(0),
+		"last":  pageLink(lastPage),
+	}
+	if pageNumber < lastPage {
+		links["next"] = pageLink(pageNumber + 1)
+	}
+	if pageNumber > 0 {
+		links["prev"] = pageLink(pageNumber - 1)
+	}
+	return links
+}
@@ -0,0 +1,109 @@
+// Package fakeapi is an in-process fake of the Form3 account API, built on
+// httptest, to run the client against without the docker-compose stack.
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const (
+	APIVersionPath  = "/v1"
+	accountsPath    = APIVersionPath + "/organisation/accounts"
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Server is a running fake account API, its state lives in memory and is
+// lost when it's closed.
+type Server struct {
+	*httptest.Server
+	store *store
+}
+
+// NewServer starts a fake account API, call Close when done.
+func NewServer() *Server {
+	store := newStore()
+	return &Server{
+		Server: httptest.NewServer(handler{store}),
+		store:  store,
+	}
+}
+
+// BaseURL returns the URL to build the client with, including the API version.
+func (s *Server) BaseURL() string {
+	return s.URL + APIVersionPath
+}
+
+// Reset removes all the stored resources.
+func (s *Server) Reset() {
+	s.store.reset()
+}
+
+type store struct {
+	mu       sync.Mutex
+	accounts []resources.Resource
+	now      func() time.Time
+}
+
+func newStore() *store {
+	return &store{now: time.Now}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = nil
+}
+
+type handler struct {
+	store *store
+}
+
+// NewHandler returns the fake API handler, to mount it in your own server.
+func NewHandler() http.Handler {
+	return handler{newStore()}
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == accountsPath && r.Method == http.MethodPost:
+		h.createAccount(w, r)
+	case path == accountsPath && r.Method == http.MethodGet:
+		h.listAccounts(w, r)
+	case strings.HasPrefix(path, accountsPath+"/"):
+		id := strings.TrimPrefix(path, accountsPath+"/")
+		switch r.Method {
+		case http.MethodGet:
+			h.fetchAccount(w, id)
+		case http.MethodDelete:
+			h.deleteAccount(w, r, id)
+		case http.MethodPatch:
+			h.updateAccount(w, r, id)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "path not found")
+	}
+}
+
+func writeData(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeData(w, statusCode, resources.BadRequestData{ErrorMessage: message})
+}
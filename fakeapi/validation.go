@@ -0,0 +1,62 @@
+package fakeapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const validationFailurePrefix = "validation failure list:\n"
+
+var accountAttributesPatterns = map[string]*regexp.Regexp{
+	"country":       regexp.MustCompile(`^[A-Z]{2}$`),
+	"base_currency": regexp.MustCompile(`^[A-Z]{3}$`),
+	"bank_id":       regexp.MustCompile(`^[A-Z0-9]{0,16}$`),
+	"bic":           regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`),
+	"iban":          regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`),
+}
+
+// validateAccount returns the same messages as the real API, nested
+// "validation failure list" lines included, or an empty string.
+func validateAccount(account resources.Resource) string {
+	failures := []string{}
+	if !uuidRegexp.MatchString(account.ID) {
+		failures = append(failures, fmt.Sprintf("id in body must be of type uuid: %q", account.ID))
+	}
+	if !uuidRegexp.MatchString(account.OrganisationID) {
+		failures = append(failures, fmt.Sprintf("organisation_id in body must be of type uuid: %q", account.OrganisationID))
+	}
+	if account.ResourceType != resources.AccountType {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [%s]", resources.AccountType))
+	}
+	if len(failures) > 0 {
+		return strings.Repeat(validationFailurePrefix, 2) + strings.Join(failures, "\n")
+	}
+
+	if account.Attributes == nil {
+		return strings.Repeat(validationFailurePrefix, 2) + "attributes in body is required"
+	}
+	if _, ok := account.Attributes["country"]; !ok {
+		failures = append(failures, "country in body is required")
+	}
+	for _, attribute := range []string{"country", "base_currency", "bank_id", "bic", "iban"} {
+		value, ok := account.Attributes[attribute]
+		if !ok {
+			continue
+		}
+		text, isString := value.(string)
+		if !isString || !accountAttributesPatterns[attribute].MatchString(text) {
+			failures = append(failures, fmt.Sprintf(
+				"%s in body should match '%s'",
+				attribute,
+				accountAttributesPatterns[attribute].String(),
+			))
+		}
+	}
+	if len(failures) > 0 {
+		return strings.Repeat(validationFailurePrefix, 3) + strings.Join(failures, "\n")
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+// Package metrics implements the client MetricsRecorder for Prometheus.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/regiluze/form3-account-api-client/client"
+)
+
+const namespace = "form3_client"
+
+var (
+	requestLabels = []string{"resource", "operation", "status_class"}
+	retryLabels   = []string{"resource", "operation"}
+)
+
+// PrometheusRecorder exports the client metrics as Prometheus metrics:
+//
+//   - form3_client_requests_total, requests by resource, operation and status class.
+//   - form3_client_request_duration_seconds, latency histogram with the same labels.
+//   - form3_client_errors_total, failed requests with the same labels.
+//   - form3_client_retries_total, retries by resource and operation.
+type PrometheusRecorder struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+}
+
+// NewPrometheusRecorder creates the recorder and registers its metrics in
+// the registerer, it returns an error when they are already registered.
+func NewPrometheusRecorder(registerer prometheus.Registerer) (*PrometheusRecorder, error) {
+	r := &PrometheusRecorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Form3 API requests by resource, operation and status class.",
+		}, requestLabels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Form3 API request latency, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, requestLabels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Form3 API requests failed with a 4xx or 5xx status code or without response.",
+		}, requestLabels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Form3 API request retries by resource and operation.",
+		}, retryLabels),
+	}
+	for _, collector := range []prometheus.Collector{r.requests, r.duration, r.errors, r.retries} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *PrometheusRecorder) ObserveRequest(info client.RequestInfo, statusClass string, latency time.Duration) {
+	labels := prometheus.Labels{
+		"resource":     string(info.ResourceName),
+		"operation":    string(info.Operation),
+		"status_class": statusClass,
+	}
+	r.requests.With(labels).Inc()
+	r.duration.With(labels).Observe(latency.Seconds())
+	if client.IsErrorStatusClass(statusClass) {
+		r.errors.With(labels).Inc()
+	}
+}
+
+func (r *PrometheusRecorder) IncRetries(info client.RequestInfo) {
+	r.retries.WithLabelValues(string(info.ResourceName), string(info.Operation)).Inc()
+}
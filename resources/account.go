@@ -0,0 +1,144 @@
+package resources
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const AccountType = "accounts"
+
+var accountAttributesKeys = jsonKeys(reflect.TypeOf(accountAttributes{}))
+
+// AccountAttributes is the typed version of the account resource attributes.
+// Attributes not covered by the struct fields are kept in Extra, so they
+// survive a round trip through the generic Resource. The boolean attributes
+// are pointers, so false is sent and nil leaves them out.
+type AccountAttributes struct {
+	Country                 string                 `json:"country,omitempty"`
+	BaseCurrency            string                 `json:"base_currency,omitempty"`
+	BankID                  string                 `json:"bank_id,omitempty"`
+	BankIDCode              string                 `json:"bank_id_code,omitempty"`
+	Bic                     string                 `json:"bic,omitempty"`
+	AccountNumber           string                 `json:"account_number,omitempty"`
+	Iban                    string                 `json:"iban,omitempty"`
+	CustomerID              string                 `json:"customer_id,omitempty"`
+	Name                    []string               `json:"name,omitempty"`
+	AlternativeNames        []string               `json:"alternative_names,omitempty"`
+	AccountClassification   string                 `json:"account_classification,omitempty"`
+	JointAccount            *bool                  `json:"joint_account,omitempty"`
+	AccountMatchingOptOut   *bool                  `json:"account_matching_opt_out,omitempty"`
+	SecondaryIdentification string                 `json:"secondary_identification,omitempty"`
+	Switched                *bool                  `json:"switched,omitempty"`
+	Status                  string                 `json:"status,omitempty"`
+	StatusReason            string                 `json:"status_reason,omitempty"`
+	ProcessingService       string                 `json:"processing_service,omitempty"`
+	UserDefinedInformation  string                 `json:"user_defined_information,omitempty"`
+	ValidationType          string                 `json:"validation_type,omitempty"`
+	ReferenceMask           string                 `json:"reference_mask,omitempty"`
+	AcceptanceQualifier     string                 `json:"acceptance_qualifier,omitempty"`
+	Extra                   map[string]interface{} `json:"-"`
+}
+
+// Bool returns a pointer to the value, to set the boolean attributes.
+func Bool(value bool) *bool {
+	return &value
+}
+
+// accountAttributes has the same fields as AccountAttributes without its
+// JSON methods, to avoid recursion when (un)marshalling.
+type accountAttributes AccountAttributes
+
+func (a AccountAttributes) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(accountAttributes(a))
+	if err != nil || len(a.Extra) == 0 {
+		return data, err
+	}
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, err
+	}
+	for key, value := range a.Extra {
+		if _, ok := accountAttributesKeys[key]; !ok {
+			attributes[key] = value
+		}
+	}
+	return json.Marshal(attributes)
+}
+
+func (a *AccountAttributes) UnmarshalJSON(data []byte) error {
+	var typed accountAttributes
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return err
+	}
+	for key := range accountAttributesKeys {
+		delete(attributes, key)
+	}
+	typed.Extra = nil
+	if len(attributes) > 0 {
+		typed.Extra = attributes
+	}
+	*a = AccountAttributes(typed)
+	return nil
+}
+
+// NewAccountAttributes converts a generic attributes map into AccountAttributes.
+func NewAccountAttributes(attributes map[string]interface{}) (AccountAttributes, error) {
+	var accountAttributes AccountAttributes
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return accountAttributes, err
+	}
+	err = json.Unmarshal(data, &accountAttributes)
+	return accountAttributes, err
+}
+
+// ToMap converts the attributes into the generic map used by Resource.
+func (a AccountAttributes) ToMap() (map[string]interface{}, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// AccountResource is the typed version of an account Resource.
+type AccountResource = TypedResource[AccountAttributes]
+
+func NewAccountResource(resource Resource) (AccountResource, error) {
+	return NewTypedResource[AccountAttributes](resource)
+}
+
+// ResourceName returns the name of the account resource.
+func (a AccountAttributes) ResourceName() ResourceName {
+	return Account
+}
+
+// Endpoint returns the path of the accounts endpoint.
+func (a AccountAttributes) Endpoint() string {
+	return "organisation/accounts"
+}
+
+// ResourceType returns the JSON:API type of the accounts.
+func (a AccountAttributes) ResourceType() string {
+	return AccountType
+}
+
+func jsonKeys(t reflect.Type) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = struct{}{}
+		}
+	}
+	return keys
+}
@@ -3,7 +3,9 @@ package resources
 type ResourceName string
 
 const (
-	Account ResourceName = "account"
+	Account      ResourceName = "account"
+	Organisation ResourceName = "organisation"
+	Payment      ResourceName = "payment"
 )
 
 type DataContainer struct {
@@ -12,7 +14,9 @@ type DataContainer struct {
 }
 
 type ListDataContainer struct {
-	Data []Resource `json:"data"`
+	Data  []Resource             `json:"data"`
+	Links map[string]string      `json:"links,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
 }
 
 type Resource struct {
@@ -39,7 +43,7 @@ func NewDataContainer(resource Resource) DataContainer {
 
 func NewAccount(id, organisationId string, attributes map[string]interface{}) Resource {
 	return Resource{
-		ResourceType:   "accounts",
+		ResourceType:   AccountType,
 		ID:             id,
 		OrganisationID: organisationId,
 		Attributes:     attributes,
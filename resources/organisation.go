@@ -0,0 +1,43 @@
+package resources
+
+const OrganisationType = "organisations"
+
+// OrganisationAttributes are the attributes of an organisation unit. The
+// OrganisationID of an organisation unit is its parent organisation.
+type OrganisationAttributes struct {
+	Name    string   `json:"name"`
+	Address []string `json:"address,omitempty"`
+	City    string   `json:"city,omitempty"`
+	Country string   `json:"country,omitempty"`
+}
+
+// ResourceName returns the name of the organisation resource.
+func (a OrganisationAttributes) ResourceName() ResourceName {
+	return Organisation
+}
+
+// Endpoint returns the path of the organisation units endpoint.
+func (a OrganisationAttributes) Endpoint() string {
+	return "organisation/units"
+}
+
+// ResourceType returns the JSON:API type of the organisations.
+func (a OrganisationAttributes) ResourceType() string {
+	return OrganisationType
+}
+
+// OrganisationResource is the typed version of an organisation Resource.
+type OrganisationResource = TypedResource[OrganisationAttributes]
+
+func NewOrganisationResource(resource Resource) (OrganisationResource, error) {
+	return NewTypedResource[OrganisationAttributes](resource)
+}
+
+func NewOrganisation(id, parentOrganisationID string, attributes map[string]interface{}) Resource {
+	return Resource{
+		ResourceType:   OrganisationType,
+		ID:             id,
+		OrganisationID: parentOrganisationID,
+		Attributes:     attributes,
+	}
+}
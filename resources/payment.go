@@ -0,0 +1,95 @@
+package resources
+
+import "strings"
+
+const (
+	PaymentType = "payments"
+
+	AccountNumberCodeBBAN = "BBAN"
+	AccountNumberCodeIBAN = "IBAN"
+)
+
+// PaymentAttributes are the attributes of a payment. Amount is a decimal
+// string like "100.21" in the Currency, to avoid float rounding, and
+// ProcessingDate is a YYYY-MM-DD date.
+type PaymentAttributes struct {
+	Amount               string       `json:"amount"`
+	Currency             string       `json:"currency"`
+	BeneficiaryParty     PaymentParty `json:"beneficiary_party"`
+	DebtorParty          PaymentParty `json:"debtor_party"`
+	PaymentID            string       `json:"payment_id,omitempty"`
+	PaymentPurpose       string       `json:"payment_purpose,omitempty"`
+	PaymentScheme        string       `json:"payment_scheme,omitempty"`
+	PaymentType          string       `json:"payment_type,omitempty"`
+	ProcessingDate       string       `json:"processing_date,omitempty"`
+	SchemePaymentType    string       `json:"scheme_payment_type,omitempty"`
+	SchemePaymentSubType string       `json:"scheme_payment_sub_type,omitempty"`
+	Reference            string       `json:"reference,omitempty"`
+	EndToEndReference    string       `json:"end_to_end_reference,omitempty"`
+	NumericReference     string       `json:"numeric_reference,omitempty"`
+}
+
+// PaymentParty is the beneficiary or debtor of a payment with its account
+// reference. AccountNumberCode tells whether AccountNumber is a BBAN or an
+// IBAN.
+type PaymentParty struct {
+	AccountName       string `json:"account_name,omitempty"`
+	AccountNumber     string `json:"account_number"`
+	AccountNumberCode string `json:"account_number_code,omitempty"`
+	AccountType       int    `json:"account_type,omitempty"`
+	Address           string `json:"address,omitempty"`
+	BankID            string `json:"bank_id,omitempty"`
+	BankIDCode        string `json:"bank_id_code,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// NewPaymentParty builds the party of a payment referencing the account,
+// with its account number, or its IBAN when it has no account number.
+func NewPaymentParty(account AccountResource) PaymentParty {
+	attributes := account.Attributes
+	name := strings.Join(attributes.Name, " ")
+	party := PaymentParty{
+		AccountName:       name,
+		AccountNumber:     attributes.AccountNumber,
+		AccountNumberCode: AccountNumberCodeBBAN,
+		BankID:            attributes.BankID,
+		BankIDCode:        attributes.BankIDCode,
+		Name:              name,
+	}
+	if party.AccountNumber == "" && attributes.Iban != "" {
+		party.AccountNumber = attributes.Iban
+		party.AccountNumberCode = AccountNumberCodeIBAN
+	}
+	return party
+}
+
+// ResourceName returns the name of the payment resource.
+func (a PaymentAttributes) ResourceName() ResourceName {
+	return Payment
+}
+
+// Endpoint returns the path of the payments endpoint.
+func (a PaymentAttributes) Endpoint() string {
+	return "transaction/payments"
+}
+
+// ResourceType returns the JSON:API type of the payments.
+func (a PaymentAttributes) ResourceType() string {
+	return PaymentType
+}
+
+// PaymentResource is the typed version of a payment Resource.
+type PaymentResource = TypedResource[PaymentAttributes]
+
+func NewPaymentResource(resource Resource) (PaymentResource, error) {
+	return NewTypedResource[PaymentAttributes](resource)
+}
+
+func NewPayment(id, organisationID string, attributes map[string]interface{}) Resource {
+	return Resource{
+		ResourceType:   PaymentType,
+		ID:             id,
+		OrganisationID: organisationID,
+		Attributes:     attributes,
+	}
+}
@@ -0,0 +1,69 @@
+package resources
+
+import "encoding/json"
+
+// ResourceAttributes describes a resource type of the API through the
+// struct of its attributes: the name used by the client, the endpoint path
+// and the JSON:API type. The methods are called on the zero value.
+type ResourceAttributes interface {
+	ResourceName() ResourceName
+	Endpoint() string
+	ResourceType() string
+}
+
+// TypedResource is the typed version of a Resource with T attributes.
+type TypedResource[T ResourceAttributes] struct {
+	ID             string
+	Version        int
+	OrganisationID string
+	Attributes     T
+	CreatedOn      string
+	ModifiedOn     string
+	// Relationships are kept as they are, so a fetched resource can be
+	// updated without losing them.
+	Relationships map[string]interface{}
+}
+
+// NewTypedResource converts a generic Resource into a TypedResource,
+// decoding its attributes map into T.
+func NewTypedResource[T ResourceAttributes](resource Resource) (TypedResource[T], error) {
+	var attributes T
+	data, err := json.Marshal(resource.Attributes)
+	if err != nil {
+		return TypedResource[T]{}, err
+	}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return TypedResource[T]{}, err
+	}
+	return TypedResource[T]{
+		ID:             resource.ID,
+		Version:        resource.Version,
+		OrganisationID: resource.OrganisationID,
+		Attributes:     attributes,
+		CreatedOn:      resource.CreatedOn,
+		ModifiedOn:     resource.ModifiedOn,
+		Relationships:  resource.Relationships,
+	}, nil
+}
+
+// Resource converts the typed resource into the generic Resource.
+func (r TypedResource[T]) Resource() (Resource, error) {
+	data, err := json.Marshal(r.Attributes)
+	if err != nil {
+		return Resource{}, err
+	}
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return Resource{}, err
+	}
+	return Resource{
+		ResourceType:   r.Attributes.ResourceType(),
+		ID:             r.ID,
+		Version:        r.Version,
+		OrganisationID: r.OrganisationID,
+		Attributes:     attributes,
+		CreatedOn:      r.CreatedOn,
+		ModifiedOn:     r.ModifiedOn,
+		Relationships:  r.Relationships,
+	}, nil
+}
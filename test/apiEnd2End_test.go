@@ -4,6 +4,7 @@ package test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/fakeapi"
 	"github.com/regiluze/form3-account-api-client/resources"
 )
 
@@ -29,6 +31,10 @@ func init() {
 	if len(baseURL) == 0 {
 		baseURL = defaultBaseURL
 	}
+	// Run the suite against the in-process fake API, no docker-compose needed
+	if os.Getenv("FORM3_API_FAKE") == "true" {
+		baseURL = fakeapi.NewServer().BaseURL()
+	}
 }
 
 var _ = Describe("Account API e2e test suite", func() {
@@ -97,7 +103,7 @@ var _ = Describe("Account API e2e test suite", func() {
 					)
 				})
 			})
-			It("returns ErrResponseStatusCode error when account id already exists", func() {
+			It("returns ErrConflict error when account id already exists", func() {
 				ukAccountID, ukOrganisationID, err := BuildRandomUUIDs()
 				Expect(err).To(BeNil())
 				accountData := BuildUKAccountWithCoP(ukAccountID, ukOrganisationID)
@@ -112,14 +118,21 @@ var _ = Describe("Account API e2e test suite", func() {
 
 				expectedURL := fmt.Sprintf("%s/organisation/accounts", baseURL)
 				Expect(resp).To(BeNil())
-				Expect(err).Should(
-					MatchError(
-						NewErrResponseStatusCode(
-							"POST",
-							expectedURL,
-							http.StatusConflict,
-						)),
-				)
+				Expect(errors.Is(err, ErrConflict{})).To(BeTrue())
+				Expect(errors.Is(err, NewErrResponseStatusCode("POST", expectedURL, http.StatusConflict))).To(BeTrue())
+				defer removeResources(ctx, apiClient, ukAccountID)
+			})
+			It("returns the existing account when it's created again with the same idempotency key", func() {
+				ukAccountID, ukOrganisationID, err := BuildRandomUUIDs()
+				Expect(err).To(BeNil())
+				accountData := BuildUKAccountWithCoP(ukAccountID, ukOrganisationID)
+				_, err = apiClient.Create(ctx, resources.Account, accountData, WithIdempotencyKey(ukAccountID))
+				Expect(err).To(BeNil())
+
+				resp, err := apiClient.Create(ctx, resources.Account, accountData, WithIdempotencyKey(ukAccountID))
+
+				Expect(err).To(BeNil())
+				Expect(resp.Data.ID).To(Equal(ukAccountID))
 				defer removeResources(ctx, apiClient, ukAccountID)
 			})
 		})
@@ -206,6 +219,43 @@ var _ = Describe("Account API e2e test suite", func() {
 				Expect(resp.Data[0].ID).To(Equal(ukAccountID2))
 				defer removeResources(ctx, apiClient, ukAccountID1, ukAccountID2, ukAccountID3)
 			})
+			It("iterates over every account following the next page links", func() {
+				ukAccountID1 := addResource(ctx, apiClient)
+				ukAccountID2 := addResource(ctx, apiClient)
+				ukAccountID3 := addResource(ctx, apiClient)
+
+				it := apiClient.ListAll(ctx, resources.Account, emptyFilter, 1)
+				ids := []string{}
+				for it.Next() {
+					ids = append(ids, it.Resource().ID)
+				}
+
+				Expect(it.Err()).To(BeNil())
+				Expect(ids).To(ContainElement(ukAccountID1))
+				Expect(ids).To(ContainElement(ukAccountID2))
+				Expect(ids).To(ContainElement(ukAccountID3))
+				defer removeResources(ctx, apiClient, ukAccountID1, ukAccountID2, ukAccountID3)
+			})
+			It("returns only the accounts matching the filter parameters", func() {
+				ukAccountID := addResource(ctx, apiClient)
+				filter := map[string]interface{}{
+					"country": []string{"FR", "DE"},
+				}
+
+				resp, err := apiClient.List(
+					ctx,
+					resources.Account,
+					filter,
+					0,
+					100,
+				)
+
+				Expect(err).To(BeNil())
+				for _, account := range resp.Data {
+					Expect(account.ID).NotTo(Equal(ukAccountID))
+				}
+				defer removeResources(ctx, apiClient, ukAccountID)
+			})
 			Context("unhappy path", func() {
 				It("returns ErrResponseStatusCode error when page number and size are negative numbers", func() {
 					pageNumber := -1
@@ -241,6 +291,14 @@ var _ = Describe("Account API e2e test suite", func() {
 
 				Expect(err).To(BeNil())
 			})
+			It("returns ErrVersionMismatch error when the version is not the current one", func() {
+				ukAccountID := addResource(ctx, apiClient)
+
+				err := apiClient.Delete(ctx, resources.Account, ukAccountID, defaultVersion+1)
+
+				Expect(errors.Is(err, ErrVersionMismatch{})).To(BeTrue())
+				defer removeResources(ctx, apiClient, ukAccountID)
+			})
 			It("returns nil error when account id not exists", func() {
 				ukAccountID, _, err := BuildRandomUUIDs()
 				Expect(err).To(BeNil())
@@ -0,0 +1,197 @@
+// +build unit
+
+package test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	"github.com/regiluze/form3-account-api-client/bankdetails"
+)
+
+// Weights for the test cases of the VocaLink modulus checking
+// specification. The MOD10, MOD11 and DBLAL examples use the weights of the
+// specification, the rows of the exception test cases are made up to give
+// the published results, as well as the 999000 range.
+const modulusWeights = `
+089000 089999 MOD10    0    0    0    0    0    0    7    1    3    7    1    3    7    1
+107999 107999 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1
+202959 202959 MOD11    0    0    0    0    0    0    7    1    3    7    1    3    7    1
+202959 202959 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1
+203099 203099 MOD11    0    0    0    0    0    0    7    1    3    7    1    3    7    1
+203099 203099 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1
+871427 872427 MOD11    3    2    5    4    3    2    5    4    3    2    5    4    3    2   10
+871427 872427 MOD11    1    2    2    2    2    2    2    2    2    2    2    2    2    2   11
+820000 827999 MOD10    0    0    0    0    0    0    2    1    3    2    1    3    2    1
+820000 827999 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1    3
+134020 134020 MOD11    0    0    0    0    0    1    2    1    2    1    2    1    2    1    4
+118765 118765 DBLAL    0    1    2    2    2    2    2    2    2    2    2    2    2    2    1
+200915 200915 DBLAL    0    0    0    0    0    0    7    1    3    7    1    3    7    1    6
+772798 772798 MOD11    0    0    1    3    2    1    3    2    1    3    2    1    3    2    7
+086090 086090 MOD11    0    0    0    0    1    2    1    2    1    2    1    2    1    2    8
+070116 074456 MOD11    0    1    4    3    2    4    3    2    4    3    2    4    3    2   12
+070116 074456 MOD10    0    0    0    5    4    3    2    1    5    4    3    2    1    5   13
+938000 938696 MOD11    7    6    5    4    3    2    7    6    5    4    3    2    0    0    5
+938000 938696 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    0    0    5
+309070 309070 MOD11    0    0    1    2    5    3    6    4    8    7   10    9    3    1    2
+309070 309070 MOD11    0    0    0    0    0    0    8    7    6    5    4    3    2    1    9
+180002 180002 MOD11    0    0    0    0    0    0    2    7    6    5    4    3    2    1   14
+999000 999999 MOD11    0    0    0    0    0    0    0    0    0    0    0    0    0    0    4
+999000 999999 DBLAL    2    1    2    1    2    1    2    1    2    1    2    1    2    1   14
+`
+
+var _ = Describe("Bank details utilities", func() {
+	var (
+		r = rand.New(rand.NewSource(1))
+	)
+
+	Context("IBAN", func() {
+		It("accepts valid IBANs in electronic and print format", func() {
+			Expect(bankdetails.ValidateIBAN("GB33BUKB20201555555555")).To(Succeed())
+			Expect(bankdetails.ValidateIBAN("DE89 3704 0044 0532 0130 00")).To(Succeed())
+			Expect(bankdetails.ValidateIBAN("fr1420041010050500013m02606")).To(Succeed())
+		})
+		It("rejects IBANs with wrong check digits", func() {
+			err := bankdetails.ValidateIBAN("GB34BUKB20201555555555")
+
+			Expect(err).Should(MatchError(bankdetails.NewErrInvalidValue("IBAN", "GB34BUKB20201555555555", "wrong check digits")))
+		})
+		It("rejects IBANs with the wrong length for the country", func() {
+			err := bankdetails.ValidateIBAN("GB33BUKB2020155555555")
+
+			Expect(err.(bankdetails.ErrInvalidValue).Reason()).To(Equal("length must be 22 for country GB"))
+		})
+		It("rejects IBANs of countries without IBAN", func() {
+			Expect(errors.Is(bankdetails.ValidateIBAN("US33BUKB20201555555555"), bankdetails.ErrInvalidValue{})).To(BeTrue())
+		})
+		It("generates valid IBANs", func() {
+			for _, country := range []string{"GB", "DE", "FR", "ES", "IT", "NL", "NO"} {
+				iban, err := bankdetails.GenerateIBAN(r, country)
+
+				Expect(err).To(BeNil())
+				Expect(iban).To(HavePrefix(country))
+				Expect(bankdetails.ValidateIBAN(iban)).To(Succeed())
+			}
+		})
+	})
+	Context("BIC", func() {
+		It("accepts valid 8 and 11 characters BICs", func() {
+			Expect(bankdetails.ValidateBIC("NWBKGB22")).To(Succeed())
+			Expect(bankdetails.ValidateBIC("DEUTDEFF500")).To(Succeed())
+		})
+		It("rejects malformed BICs", func() {
+			Expect(bankdetails.ValidateBIC("NWBKGB2")).NotTo(Succeed())
+			Expect(bankdetails.ValidateBIC("NWBKGB22X")).NotTo(Succeed())
+		})
+		It("rejects BICs with an unknown country", func() {
+			err := bankdetails.ValidateBIC("NWBKQQ22")
+
+			Expect(err.(bankdetails.ErrInvalidValue).Reason()).To(Equal("unknown country QQ"))
+		})
+		It("generates valid BICs", func() {
+			bic, err := bankdetails.GenerateBIC(r, "GB")
+
+			Expect(err).To(BeNil())
+			Expect(bic[4:6]).To(Equal("GB"))
+			Expect(bankdetails.ValidateBIC(bic)).To(Succeed())
+		})
+	})
+	Context("UK sort code and account number modulus checking", func() {
+		var checker *bankdetails.ModulusChecker
+
+		BeforeEach(func() {
+			var err error
+			checker, err = bankdetails.ParseModulusWeights(strings.NewReader(modulusWeights))
+			Expect(err).To(BeNil())
+		})
+
+		It("passes the VocaLink MOD10, MOD11 and DBLAL examples", func() {
+			Expect(checker.Validate("089999", "66374958")).To(Succeed())
+			Expect(checker.Validate("107999", "88837491")).To(Succeed())
+			Expect(checker.Validate("202959", "63748472")).To(Succeed())
+		})
+		It("fails when a digit changes", func() {
+			Expect(checker.Validate("089999", "66374959")).NotTo(Succeed())
+			Expect(checker.Validate("107999", "88837492")).NotTo(Succeed())
+			Expect(checker.Validate("202959", "63748473")).NotTo(Succeed())
+		})
+		It("fails when any of the two checks of a sort code fails", func() {
+			Expect(checker.Validate("203099", "66831036")).To(MatchError(bankdetails.NewErrInvalidValue("UK account", "203099 66831036", "DBLAL modulus check failed")))
+			Expect(checker.Validate("203099", "58716970")).To(MatchError(bankdetails.NewErrInvalidValue("UK account", "203099 58716970", "MOD11 modulus check failed")))
+		})
+		It("passes when any of the two checks passes with exceptions 10 and 11", func() {
+			Expect(checker.Validate("871427", "46238510")).To(Succeed())
+			Expect(checker.Validate("872427", "46238510")).To(Succeed())
+		})
+		It("zeroises the weights u to b with exception 10", func() {
+			Expect(checker.Validate("871427", "09123496")).To(Succeed())
+			Expect(checker.Validate("871427", "99123496")).To(Succeed())
+		})
+		It("passes when any of the two checks passes with exceptions 12 and 13", func() {
+			Expect(checker.Validate("074456", "12345112")).To(Succeed())
+			Expect(checker.Validate("070116", "34012583")).To(Succeed())
+			Expect(checker.Validate("074456", "11104102")).To(Succeed())
+		})
+		It("skips the second check when c is 6 or 9 with exception 3", func() {
+			Expect(checker.Validate("820000", "73688637")).To(Succeed())
+			Expect(checker.Validate("827999", "73988638")).To(Succeed())
+			Expect(checker.Validate("827101", "28748352")).To(Succeed())
+		})
+		It("checks the remainder against the last two digits with exception 4", func() {
+			Expect(checker.Validate("134020", "63849203")).To(Succeed())
+			Expect(checker.Validate("999000", "12345600")).To(Succeed())
+			Expect(checker.Validate("999000", "12345601")).NotTo(Succeed())
+		})
+		It("adds 27 to the total with exception 1", func() {
+			Expect(checker.Validate("118765", "64371389")).To(Succeed())
+			Expect(checker.Validate("118765", "64371388")).NotTo(Succeed())
+		})
+		It("doesn't check foreign currency accounts with exception 6", func() {
+			Expect(checker.Validate("200915", "41011166")).To(Succeed())
+		})
+		It("zeroises the weights u to b when g is 9 with exception 7", func() {
+			Expect(checker.Validate("772798", "99345694")).To(Succeed())
+		})
+		It("replaces the sort code with 090126 with exception 8", func() {
+			Expect(checker.Validate("086090", "06774744")).To(Succeed())
+		})
+		It("skips the checks with exceptions it doesn't implement", func() {
+			Expect(checker.Validate("938611", "07806039")).To(Succeed())
+			Expect(checker.Validate("938063", "55065200")).To(Succeed())
+			Expect(checker.Validate("309070", "02355688")).To(Succeed())
+			Expect(checker.Validate("309070", "12345668")).To(Succeed())
+			Expect(checker.Validate("309070", "12345677")).To(Succeed())
+			Expect(checker.Validate("309070", "99345694")).To(Succeed())
+			Expect(checker.Validate("180002", "00000190")).To(Succeed())
+		})
+		It("accepts any account number of a sort code with only exceptions it doesn't implement", func() {
+			Expect(checker.Validate("180002", "12345678")).To(Succeed())
+			Expect(checker.Validate("309070", "99999999")).To(Succeed())
+		})
+		It("keeps checking the other rows of a sort code with an exception it doesn't implement", func() {
+			Expect(checker.Validate("999000", "12345601")).To(MatchError(bankdetails.NewErrInvalidValue("UK account", "999000 12345601", "MOD11 modulus check failed")))
+		})
+		It("accepts sort codes out of the weights table", func() {
+			Expect(checker.Validate("400300", "12345678")).To(Succeed())
+		})
+		It("rejects malformed sort codes and account numbers", func() {
+			Expect(checker.Validate("40-03-00", "12345678")).NotTo(Succeed())
+			Expect(checker.Validate("400300", "1234")).NotTo(Succeed())
+		})
+		It("returns an error when the weights table is malformed", func() {
+			_, err := bankdetails.ParseModulusWeights(strings.NewReader("089000 089999 MOD12 0 0"))
+
+			Expect(err).NotTo(BeNil())
+		})
+		It("generates valid sort code and account number pairs", func() {
+			sortCode, accountNumber, err := checker.GenerateUKAccount(r)
+
+			Expect(err).To(BeNil())
+			Expect(checker.Validate(sortCode, accountNumber)).To(Succeed())
+		})
+	})
+})
@@ -1,6 +1,11 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
 	"github.com/google/uuid"
 	"github.com/regiluze/form3-account-api-client/resources"
 )
@@ -64,3 +69,67 @@ func buildUKAccountWithCoP() map[string]interface{} {
 		"secondary_identification": "A1B2C3D4",
 	}
 }
+
+func BuildUKAccountResourceWithCoP(id, organisationID string) resources.AccountResource {
+	return resources.AccountResource{
+		ID:             id,
+		OrganisationID: organisationID,
+		Attributes: resources.AccountAttributes{
+			Country:                 "GB",
+			BaseCurrency:            "GBP",
+			BankID:                  "400300",
+			BankIDCode:              "GBDSC",
+			Bic:                     "NWBKGB22",
+			Name:                    []string{"Samantha Holder"},
+			AlternativeNames:        []string{"Sam Holder"},
+			AccountClassification:   "Personal",
+			JointAccount:            resources.Bool(false),
+			AccountMatchingOptOut:   resources.Bool(false),
+			SecondaryIdentification: "A1B2C3D4",
+		},
+	}
+}
+
+func BuildPaymentResource(id, organisationID string) resources.PaymentResource {
+	return resources.PaymentResource{
+		ID:             id,
+		OrganisationID: organisationID,
+		Attributes: resources.PaymentAttributes{
+			Amount:   "100.10",
+			Currency: "GBP",
+			BeneficiaryParty: resources.PaymentParty{
+				AccountName:       "W Owens",
+				AccountNumber:     "31926819",
+				AccountNumberCode: resources.AccountNumberCodeBBAN,
+				BankID:            "403000",
+				BankIDCode:        "GBDSC",
+				Name:              "Wilfred Jeremiah Owens",
+			},
+			DebtorParty: resources.PaymentParty{
+				AccountName:       "EJ Brown Black",
+				AccountNumber:     "GB29XABC10161234567801",
+				AccountNumberCode: resources.AccountNumberCodeIBAN,
+				BankID:            "203301",
+				BankIDCode:        "GBDSC",
+				Name:              "Emelia Jane Brown",
+			},
+			PaymentScheme:     "FPS",
+			PaymentType:       "Credit",
+			ProcessingDate:    "2021-01-18",
+			SchemePaymentType: "ImmediatePayment",
+			Reference:         "Payment for Em's piano lessons",
+			EndToEndReference: "Wil piano Jan",
+			NumericReference:  "1002001",
+		},
+	}
+}
+
+// buildResponse returns a response with the status code and the data as
+// JSON body.
+func buildResponse(statusCode int, data interface{}) *http.Response {
+	body, _ := json.Marshal(data)
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
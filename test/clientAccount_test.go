@@ -0,0 +1,139 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Account typed helpers", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+	})
+
+	Context("Converting account attributes", func() {
+		It("converts a generic attributes map into typed attributes", func() {
+			attributes, err := resources.NewAccountAttributes(buildUKAccountWithCoP())
+
+			Expect(err).To(BeNil())
+			Expect(attributes.BankIDCode).To(Equal("GBDSC"))
+			Expect(attributes.Name).To(Equal([]string{"Samantha Holder"}))
+			Expect(attributes.Extra).To(BeNil())
+		})
+		It("keeps unknown attributes in Extra after a round trip", func() {
+			attributesMap := buildUKAccountWithoutCoP()
+			attributesMap["unknown_field"] = "value"
+
+			attributes, err := resources.NewAccountAttributes(attributesMap)
+			Expect(err).To(BeNil())
+			Expect(attributes.Extra).To(Equal(map[string]interface{}{"unknown_field": "value"}))
+
+			converted, err := attributes.ToMap()
+			Expect(err).To(BeNil())
+			Expect(converted).To(Equal(map[string]interface{}{
+				"country":       "GB",
+				"base_currency": "GBP",
+				"bank_id":       "400300",
+				"bank_id_code":  "GBDSC",
+				"bic":           "NWBKGB22",
+				"unknown_field": "value",
+			}))
+		})
+		It("doesn't override typed fields with Extra values", func() {
+			attributes := resources.AccountAttributes{
+				Country: "GB",
+				Extra:   map[string]interface{}{"country": "FR"},
+			}
+
+			converted, err := attributes.ToMap()
+
+			Expect(err).To(BeNil())
+			Expect(converted["country"]).To(Equal("GB"))
+		})
+		It("keeps the false boolean attributes and leaves out the unset ones", func() {
+			attributes := resources.AccountAttributes{
+				Country:      "GB",
+				JointAccount: resources.Bool(false),
+				Switched:     resources.Bool(true),
+			}
+
+			converted, err := attributes.ToMap()
+
+			Expect(err).To(BeNil())
+			Expect(converted).To(Equal(map[string]interface{}{
+				"country":       "GB",
+				"joint_account": false,
+				"switched":      true,
+			}))
+		})
+	})
+	Context("FetchAccount", func() {
+		It("returns the account with typed attributes", func() {
+			data := resources.NewDataContainer(BuildUKAccountWithCoP(id, organisationID))
+			dataBt, _ := json.Marshal(data)
+			httpClientMock.EXPECT().Do(IsRequestMethod("GET")).Return(
+				&http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewReader(dataBt)),
+				},
+				nil,
+			).Times(1)
+
+			account, err := FetchAccount(ctx, client, id)
+
+			Expect(err).To(BeNil())
+			Expect(account.ID).To(Equal(id))
+			Expect(account.Attributes).To(Equal(BuildUKAccountResourceWithCoP(id, organisationID).Attributes))
+		})
+		It("returns the client error", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+			account, err := FetchAccount(ctx, client, id)
+
+			Expect(account).To(BeNil())
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("CreateAccount", func() {
+		It("sends the typed attributes as the generic resource", func() {
+			account := BuildUKAccountResourceWithCoP(id, organisationID)
+			resource, _ := account.Resource()
+			dataBt, _ := json.Marshal(resources.NewDataContainer(resource))
+			req, _ := http.NewRequest("POST", baseURL, bytes.NewBuffer(dataBt))
+			httpClientMock.EXPECT().Do(IsRequestBody(req)).Return(
+				&http.Response{
+					StatusCode: 201,
+					Body:       ioutil.NopCloser(bytes.NewReader(dataBt)),
+				},
+				nil,
+			).Times(1)
+
+			created, err := CreateAccount(ctx, client, account)
+
+			Expect(err).To(BeNil())
+			Expect(created.ID).To(Equal(id))
+			Expect(created.Attributes.Bic).To(Equal("NWBKGB22"))
+		})
+	})
+})
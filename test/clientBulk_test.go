@@ -0,0 +1,207 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Bulk operations", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+		accounts       []resources.Resource
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+		accounts = nil
+		for i := 0; i < 5; i++ {
+			accounts = append(accounts, BuildUKAccountWithCoP(uuid.New().String(), organisationID))
+		}
+	})
+
+	createdResponse := func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: 201,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+	conflictResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: 409,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error_message": "Account cannot be created as it violates a duplicate constraint"}`)),
+		}
+	}
+	accountURL := func(id string) string {
+		return fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id)
+	}
+
+	Context("BulkCreate", func() {
+		It("creates all the resources returning their results in order", func() {
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(createdResponse).Times(len(accounts))
+			var progress []BulkProgress
+			options := DefaultBulkOptions()
+			options.OnProgress = func(p BulkProgress) {
+				progress = append(progress, p)
+			}
+
+			results, err := BulkCreate(ctx, client, resources.Account, accounts, options)
+
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(len(accounts)))
+			for i, result := range results {
+				Expect(result.ID).To(Equal(accounts[i].ID))
+				Expect(result.Err).To(BeNil())
+				Expect(result.Data.ID).To(Equal(accounts[i].ID))
+			}
+			Expect(progress).To(HaveLen(len(accounts)))
+			Expect(progress[len(progress)-1]).To(Equal(BulkProgress{Total: len(accounts), Completed: len(accounts)}))
+		})
+		It("continues after a failure and returns an ErrBulkFailed error", func() {
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(req.Body)
+				if bytes.Contains(body, []byte(accounts[2].ID)) {
+					return conflictResponse(), nil
+				}
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+				return createdResponse(req)
+			}).Times(len(accounts))
+
+			results, err := BulkCreate(ctx, client, resources.Account, accounts, DefaultBulkOptions())
+
+			Expect(err).Should(MatchError(NewErrBulkFailed(1, len(accounts))))
+			Expect(errors.Is(results[2].Err, ErrConflict{})).To(BeTrue())
+			Expect(results[2].Data).To(BeNil())
+			for _, i := range []int{0, 1, 3, 4} {
+				Expect(results[i].Err).To(BeNil())
+			}
+		})
+		It("stops scheduling creations after the first failure in FailFast mode", func() {
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(createdResponse).Times(1),
+				httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(conflictResponse(), nil).Times(1),
+			)
+			options := DefaultBulkOptions()
+			options.Concurrency = 1
+			options.Mode = FailFast
+
+			results, err := BulkCreate(ctx, client, resources.Account, accounts, options)
+
+			Expect(errors.Is(err, ErrConflict{})).To(BeTrue())
+			Expect(results[0].Err).To(BeNil())
+			Expect(errors.Is(results[1].Err, ErrConflict{})).To(BeTrue())
+			for _, result := range results[2:] {
+				Expect(result.Err).Should(MatchError(NewErrBulkSkipped()))
+			}
+		})
+		It("doesn't process more resources at the same time than the concurrency", func() {
+			var inFlight, maxInFlight int32
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					max := atomic.LoadInt32(&maxInFlight)
+					if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				return createdResponse(req)
+			}).Times(len(accounts))
+			options := DefaultBulkOptions()
+			options.Concurrency = 2
+
+			_, err := BulkCreate(ctx, client, resources.Account, accounts, options)
+
+			Expect(err).To(BeNil())
+			Expect(atomic.LoadInt32(&maxInFlight)).To(Equal(int32(2)))
+		})
+		It("stops scheduling creations when the context is cancelled", func() {
+			cancelCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(createdResponse).Times(2)
+			options := DefaultBulkOptions()
+			options.Concurrency = 1
+			options.OnProgress = func(p BulkProgress) {
+				if p.Completed == 2 {
+					cancel()
+				}
+			}
+
+			results, err := BulkCreate(cancelCtx, client, resources.Account, accounts, options)
+
+			Expect(err).Should(MatchError(context.Canceled))
+			Expect(results[0].Err).To(BeNil())
+			Expect(results[1].Err).To(BeNil())
+			for _, result := range results[2:] {
+				Expect(result.Err).Should(MatchError(context.Canceled))
+			}
+		})
+		It("creates the resources with their idempotency keys", func() {
+			var mu sync.Mutex
+			keys := map[string]bool{}
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				keys[req.Header.Get(IdempotencyKeyHeader)] = true
+				mu.Unlock()
+				return createdResponse(req)
+			}).Times(len(accounts))
+			options := DefaultBulkOptions()
+			options.IdempotencyKey = func(resource resources.Resource) string {
+				return "migration-" + resource.ID
+			}
+
+			_, err := BulkCreate(ctx, client, resources.Account, accounts, options)
+
+			Expect(err).To(BeNil())
+			for _, account := range accounts {
+				Expect(keys).To(HaveKey("migration-" + account.ID))
+			}
+		})
+	})
+	Context("BulkDelete", func() {
+		It("deletes all the resources with their versions", func() {
+			items := []BulkDeleteItem{{ID: id, Version: 0}, {ID: id2, Version: 3}}
+			httpClientMock.EXPECT().Do(IsRequestURL(accountURL(id)+"?version=0")).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+			httpClientMock.EXPECT().Do(IsRequestURL(accountURL(id2)+"?version=3")).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+
+			results, err := BulkDelete(ctx, client, resources.Account, items, DefaultBulkOptions())
+
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]BulkResult{{ID: id}, {ID: id2}}))
+		})
+		It("returns the typed error of the failed deletions", func() {
+			items := []BulkDeleteItem{{ID: id, Version: 0}, {ID: id2, Version: 1}}
+			httpClientMock.EXPECT().Do(IsRequestURL(accountURL(id)+"?version=0")).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+			httpClientMock.EXPECT().Do(IsRequestURL(accountURL(id2)+"?version=1")).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+
+			results, err := BulkDelete(ctx, client, resources.Account, items, DefaultBulkOptions())
+
+			Expect(err).Should(MatchError(NewErrBulkFailed(1, 2)))
+			Expect(results[0].Err).To(BeNil())
+			Expect(errors.Is(results[1].Err, ErrNotFound{})).To(BeTrue())
+		})
+	})
+})
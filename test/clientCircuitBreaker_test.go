@@ -0,0 +1,162 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client circuit breaker", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		breaker        *CircuitBreaker
+		changes        []string
+		ctx            = context.Background()
+		coolDown       = 20 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		changes = []string{}
+		breaker = NewCircuitBreaker(CircuitBreakerSettings{
+			FailureRatio:     0.5,
+			MinRequests:      4,
+			Window:           time.Minute,
+			CoolDown:         coolDown,
+			HalfOpenRequests: 1,
+			OnStateChange: func(from, to CircuitState) {
+				changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+			},
+		})
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker))
+	})
+
+	openCircuit := func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(2)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+		for i := 0; i < 4; i++ {
+			client.Fetch(ctx, resources.Account, id)
+		}
+	}
+
+	It("stays closed while the failure ratio is under the threshold", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(3)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1)
+		for i := 0; i < 4; i++ {
+			client.Fetch(ctx, resources.Account, id)
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+		Expect(changes).To(BeEmpty())
+	})
+	It("replaces a zero failure ratio with the default one", func() {
+		breaker = NewCircuitBreaker(CircuitBreakerSettings{MinRequests: 4, Window: time.Minute, CoolDown: coolDown})
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(4)
+		for i := 0; i < 4; i++ {
+			client.Fetch(ctx, resources.Account, id)
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+	It("fails fast with the default cool down when a settings literal leaves it out", func() {
+		breaker = NewCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, MinRequests: 2})
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(2)
+		for i := 0; i < 2; i++ {
+			client.Fetch(ctx, resources.Account, id)
+		}
+
+		for i := 0; i < 4; i++ {
+			_, err := client.Fetch(ctx, resources.Account, id)
+			Expect(errors.Is(err, ErrCircuitOpen{})).To(BeTrue())
+		}
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+	})
+	It("waits for the default min requests when a settings literal leaves them out", func() {
+		breaker = NewCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, CoolDown: coolDown})
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+	It("doesn't count not found or bad request responses as failures", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(4)
+		for i := 0; i < 4; i++ {
+			client.Fetch(ctx, resources.Account, id)
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+	It("opens when the failure ratio reaches the threshold and fails fast", func() {
+		openCircuit()
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+		Expect(errors.Is(err, ErrCircuitOpen{})).To(BeTrue())
+		Expect(err).Should(MatchError(NewErrCircuitOpen("GET", fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id))))
+		Expect(changes).To(Equal([]string{"closed->open"}))
+	})
+	It("closes when the trial request succeeds after the cool down", func() {
+		openCircuit()
+		time.Sleep(coolDown)
+		Expect(breaker.State()).To(Equal(CircuitHalfOpen))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+		Expect(changes).To(Equal([]string{"closed->open", "open->half-open", "half-open->closed"}))
+	})
+	It("closes after a trial request when the half-open requests setting is zero", func() {
+		breaker = NewCircuitBreaker(CircuitBreakerSettings{FailureRatio: 0.5, MinRequests: 4, Window: time.Minute, CoolDown: coolDown})
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker))
+		openCircuit()
+		time.Sleep(coolDown)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+	It("opens again when the trial request fails", func() {
+		openCircuit()
+		time.Sleep(coolDown)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 500}, nil).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+		Expect(changes).To(Equal([]string{"closed->open", "open->half-open", "half-open->open"}))
+	})
+	It("doesn't retry requests failed because the circuit is open", func() {
+		policy := DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.MaxDelay = time.Millisecond
+		openCircuit()
+		client = NewForm3APIClient(baseURL, httpClientMock, WithCircuitBreaker(breaker), WithRetryPolicy(policy))
+
+		err := client.Delete(ctx, resources.Account, id, version)
+
+		Expect(errors.Is(err, ErrCircuitOpen{})).To(BeTrue())
+	})
+})
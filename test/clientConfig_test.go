@@ -0,0 +1,309 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// writeTempFile writes the content in a new file of the directory and
+// returns its path.
+func writeTempFile(dir, name string, content []byte) string {
+	path := filepath.Join(dir, name)
+	Expect(ioutil.WriteFile(path, content, 0600)).To(Succeed())
+	return path
+}
+
+// buildClientCertificate returns a self signed certificate and its key in
+// PEM format.
+func buildClientCertificate() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "form3-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(BeNil())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFetchResponse(w http.ResponseWriter) {
+	data, _ := json.Marshal(resources.NewDataContainer(resources.NewAccount(id, organisationID, map[string]interface{}{})))
+	w.Write(data)
+}
+
+var _ = Describe("Client construction options", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		dir            string
+		environment    map[string]string
+		ctx            = context.Background()
+	)
+
+	setEnv := func(name, value string) {
+		if _, ok := environment[name]; !ok {
+			environment[name] = os.Getenv(name)
+		}
+		os.Setenv(name, value)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		environment = map[string]string{}
+		setEnv(EnvBaseURL, "")
+		var err error
+		dir, err = ioutil.TempDir("", "form3-client")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		for name, value := range environment {
+			os.Setenv(name, value)
+		}
+		os.RemoveAll(dir)
+	})
+
+	Context("Validation", func() {
+		It("returns an ErrInvalidConfig error without base URL", func() {
+			_, err := NewClient()
+
+			Expect(err).Should(MatchError(NewErrInvalidConfig("base_url", "is required")))
+		})
+		It("returns an ErrInvalidConfig error when the base URL isn't an http URL", func() {
+			_, err := NewClient(WithBaseURL("api_base_url"))
+
+			Expect(errors.Is(err, ErrInvalidConfig{})).To(BeTrue())
+		})
+		It("returns an ErrInvalidConfig error when a custom http client is used with transport options", func() {
+			_, err := NewClient(WithBaseURL("http://localhost:8080/v1"), WithHTTPClient(httpClientMock), WithTimeout(time.Second))
+
+			Expect(errors.Is(err, ErrInvalidConfig{})).To(BeTrue())
+		})
+		It("returns an ErrInvalidConfig error with half of the credentials", func() {
+			_, err := NewClient(WithBaseURL("http://localhost:8080/v1"), WithConfigFile(
+				writeTempFile(dir, "config.yaml", []byte("client_id: client\n")),
+			))
+
+			Expect(err).Should(MatchError(NewErrInvalidConfig("client_id", "the client id and secret are both required")))
+		})
+		It("returns an ErrInvalidConfig error when a file can't be read", func() {
+			_, err := NewClient(WithBaseURL("http://localhost:8080/v1"), WithCAFile(filepath.Join(dir, "missing.pem")))
+
+			Expect(errors.Is(err, ErrInvalidConfig{})).To(BeTrue())
+		})
+		It("returns an ErrInvalidConfig error when the config file has a timeout and a custom http client is used", func() {
+			_, err := NewClient(
+				WithConfigFile(writeTempFile(dir, "config.yaml", []byte("base_url: http://form3.test/v1\ntimeout: 5s\n"))),
+				WithHTTPClient(httpClientMock),
+			)
+
+			Expect(errors.Is(err, ErrInvalidConfig{})).To(BeTrue())
+		})
+		It("returns an ErrInvalidConfig error for non positive operation timeouts", func() {
+			_, err := NewClient(WithBaseURL("http://localhost:8080/v1"), WithOperationTimeout(OperationFetch, 0))
+
+			Expect(errors.Is(err, ErrInvalidConfig{})).To(BeTrue())
+		})
+	})
+	Context("Settings", func() {
+		It("reads the base URL from the environment", func() {
+			setEnv(EnvBaseURL, "http://form3.test/v1")
+			client, err := NewClient(WithHTTPClient(httpClientMock))
+			Expect(err).To(BeNil())
+			httpClientMock.EXPECT().Do(IsRequestURL("http://form3.test/v1/organisation/accounts/"+id)).Return(buildFetchResponse(), nil).Times(1)
+
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("sends the user agent", func() {
+			client, err := NewClient(WithBaseURL("http://form3.test/v1"), WithHTTPClient(httpClientMock), WithUserAgent("payments/1.0"))
+			Expect(err).To(BeNil())
+			httpClientMock.EXPECT().Do(IsRequestHeader("User-Agent", "payments/1.0")).Return(buildFetchResponse(), nil).Times(1)
+
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("sets the timeout of the operations with one", func() {
+			client, err := NewClient(
+				WithBaseURL("http://form3.test/v1"),
+				WithHTTPClient(httpClientMock),
+				WithOperationTimeout(OperationFetch, time.Minute),
+			)
+			Expect(err).To(BeNil())
+			httpClientMock.EXPECT().Do(IsRequestMethod("GET")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				deadline, ok := req.Context().Deadline()
+				Expect(ok).To(BeTrue())
+				Expect(time.Until(deadline)).To(BeNumerically("~", time.Minute, time.Second))
+				return buildFetchResponse(), nil
+			}).Times(1)
+			httpClientMock.EXPECT().Do(IsRequestMethod("DELETE")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				_, ok := req.Context().Deadline()
+				Expect(ok).To(BeFalse())
+				return &http.Response{StatusCode: 204}, nil
+			}).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+			client.Delete(ctx, resources.Account, id, version)
+		})
+		It("times out the requests with the http client timeout", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}))
+			defer server.Close()
+			client, err := NewClient(WithBaseURL(server.URL), WithTimeout(20*time.Millisecond))
+			Expect(err).To(BeNil())
+
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("Client.Timeout"))
+		})
+		It("sends the requests through the proxy", func() {
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.String()).To(Equal("http://form3.test/v1/organisation/accounts/" + id))
+				writeFetchResponse(w)
+			}))
+			defer proxy.Close()
+			client, err := NewClient(WithBaseURL("http://form3.test/v1"), WithProxy(proxy.URL))
+			Expect(err).To(BeNil())
+
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+		})
+		It("trusts the CA file certificates and sends the client certificate", func() {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.TLS.PeerCertificates).To(HaveLen(1))
+				Expect(r.TLS.PeerCertificates[0].Subject.CommonName).To(Equal("form3-client"))
+				writeFetchResponse(w)
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			server.StartTLS()
+			defer server.Close()
+			caFile := writeTempFile(dir, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+			certPEM, keyPEM := buildClientCertificate()
+			certFile := writeTempFile(dir, "client.pem", certPEM)
+			keyFile := writeTempFile(dir, "client.key", keyPEM)
+
+			client, err := NewClient(WithBaseURL(server.URL), WithCAFile(caFile), WithClientCertificate(certFile, keyFile))
+			Expect(err).To(BeNil())
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+		})
+		It("rejects the server certificate without the CA file", func() {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeFetchResponse(w)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(WithBaseURL(server.URL))
+			Expect(err).To(BeNil())
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("Credentials", func() {
+		It("authenticates with the client credentials of the environment", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/oauth2/token" {
+					clientID, clientSecret, _ := r.BasicAuth()
+					Expect(clientID).To(Equal("client"))
+					Expect(clientSecret).To(Equal("secret"))
+					w.Write([]byte(`{"access_token": "token-1", "expires_in": 3600}`))
+					return
+				}
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer token-1"))
+				writeFetchResponse(w)
+			}))
+			defer server.Close()
+			setEnv(EnvBaseURL, server.URL+"/v1")
+			setEnv(EnvClientID, "client")
+			setEnv(EnvClientSecret, "secret")
+
+			client, err := NewClient(WithEnvironment())
+			Expect(err).To(BeNil())
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("signs the requests with the signing key of the config file", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).To(BeNil())
+			keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			Expect(err).To(BeNil())
+			keyFile := writeTempFile(dir, "signing.key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+			configFile := writeTempFile(dir, "config.yaml", []byte(fmt.Sprintf(
+				"base_url: http://form3.test/v1\nsigning_key_id: key-1\nsigning_key_file: %s\n",
+				keyFile,
+			)))
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(strings.HasPrefix(req.Header.Get("Authorization"), `Signature keyId="key-1"`)).To(BeTrue())
+				Expect(VerifyHTTPSignature(req, &privateKey.PublicKey)).To(Succeed())
+				return buildFetchResponse(), nil
+			}).Times(1)
+
+			client, err := NewClient(WithConfigFile(configFile), WithHTTPClient(httpClientMock))
+			Expect(err).To(BeNil())
+			_, err = client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("returns an ErrInvalidConfig error when the client credentials and the signing key are both set", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).To(BeNil())
+			keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			Expect(err).To(BeNil())
+			keyFile := writeTempFile(dir, "signing.key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+			setEnv(EnvBaseURL, "http://form3.test/v1")
+			setEnv(EnvClientID, "client")
+			setEnv(EnvClientSecret, "secret")
+			setEnv(EnvSigningKeyID, "key-1")
+			setEnv(EnvSigningKeyFile, keyFile)
+
+			_, err = NewClient(WithEnvironment())
+
+			Expect(err).Should(MatchError(NewErrInvalidConfig("signing_key_id", "can't be used with the client credentials, both set the Authorization header")))
+		})
+	})
+})
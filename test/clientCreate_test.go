@@ -162,6 +162,26 @@ var _ = Describe("Account api resource client CREATE method", func() {
 				MatchError(NewErrResponseStatusCode("POST", expectedURL, 403)),
 			)
 		})
+		It("returns ErrConflict error with the server message when server responses an error 409", func() {
+			accountData := BuildBasicAccountResource(id, organisationID)
+			json := `{"error_message": "Account cannot be created as it violates a duplicate constraint"}`
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 409,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+				},
+				nil,
+			).Times(1)
+
+			response, err := client.Create(ctx, resources.Account, accountData)
+
+			Expect(response).To(BeNil())
+			Expect(err).Should(
+				MatchError(
+					NewErrConflict("POST", expectedURL, "Account cannot be created as it violates a duplicate constraint")),
+			)
+			Expect(errors.Is(err, NewErrResponseStatusCode("POST", expectedURL, 409))).To(BeTrue())
+		})
 		It("returns an error with information to indentify the problem when server responses an error 400", func() {
 			accountData := BuildBasicAccountResource(id, organisationID)
 			errorData := resources.BadRequestData{
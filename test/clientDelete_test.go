@@ -6,7 +6,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 
 	gomock "github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -89,5 +91,24 @@ var _ = Describe("Account api resource client DELETE method", func() {
 					NewErrResponseStatusCode("DELETE", expectedURL, 500)),
 			)
 		})
+		It("returns ErrVersionMismatch error with the server message when server responses an error 409", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 409,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"error_message": "invalid version"}`)),
+				},
+				nil,
+			).Times(1)
+
+			err := client.Delete(ctx, resources.Account, id, version)
+
+			Expect(err).Should(
+				MatchError(
+					NewErrVersionMismatch("DELETE", expectedURL, "invalid version")),
+			)
+			var versionErr ErrVersionMismatch
+			Expect(errors.As(err, &versionErr)).To(BeTrue())
+			Expect(versionErr.Message()).To(Equal("invalid version"))
+		})
 	})
 })
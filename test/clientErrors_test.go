@@ -0,0 +1,60 @@
+// +build unit
+
+package test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client errors", func() {
+	var (
+		url = fmt.Sprintf("%s/organisation/accounts", baseURL)
+	)
+
+	Context("Matching with errors.Is", func() {
+		It("matches any error of the same type with the zero value", func() {
+			Expect(errors.Is(NewErrNotFound(url), ErrNotFound{})).To(BeTrue())
+			Expect(errors.Is(NewErrBadRequest("POST", resources.BadRequestData{ErrorMessage: "error"}), ErrBadRequest{})).To(BeTrue())
+			Expect(errors.Is(NewErrConflict("POST", url, "duplicate"), ErrConflict{})).To(BeTrue())
+			Expect(errors.Is(NewErrVersionMismatch("DELETE", url, "invalid version"), ErrVersionMismatch{})).To(BeTrue())
+			Expect(errors.Is(NewErrResponseStatusCode("GET", url, 500), ErrResponseStatusCode{})).To(BeTrue())
+		})
+		It("doesn't match errors with different values", func() {
+			Expect(errors.Is(NewErrNotFound(url), NewErrNotFound("other"))).To(BeFalse())
+			Expect(errors.Is(NewErrNotFound(url), ErrBadRequest{})).To(BeFalse())
+			Expect(errors.Is(NewErrConflict("POST", url, "duplicate"), ErrVersionMismatch{})).To(BeFalse())
+		})
+		It("matches ErrResponseStatusCode errors by status code", func() {
+			err := fmt.Errorf("wrapped: %w", NewErrResponseStatusCode("GET", url, 503))
+
+			Expect(errors.Is(err, ErrResponseStatusCode{StatusCode: 503})).To(BeTrue())
+			Expect(errors.Is(err, ErrResponseStatusCode{StatusCode: 500})).To(BeFalse())
+		})
+		It("matches conflict errors as 409 ErrResponseStatusCode errors", func() {
+			Expect(errors.Is(NewErrConflict("POST", url, "duplicate"), ErrResponseStatusCode{StatusCode: 409})).To(BeTrue())
+			Expect(errors.Is(NewErrVersionMismatch("DELETE", url, "invalid version"), ErrResponseStatusCode{StatusCode: 409})).To(BeTrue())
+		})
+	})
+	Context("Extracting with errors.As", func() {
+		It("extracts the status code error of a conflict", func() {
+			var statusErr ErrResponseStatusCode
+
+			Expect(errors.As(NewErrConflict("POST", url, "duplicate"), &statusErr)).To(BeTrue())
+			Expect(statusErr.StatusCode).To(Equal(409))
+		})
+		It("extracts the server message of a conflict", func() {
+			var conflictErr ErrConflict
+			err := fmt.Errorf("creating account: %w", NewErrConflict("POST", url, "duplicate"))
+
+			Expect(errors.As(err, &conflictErr)).To(BeTrue())
+			Expect(conflictErr.Message()).To(Equal("duplicate"))
+		})
+	})
+})
@@ -0,0 +1,137 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client request id and idempotent Create", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+		conflictBody   = `{"error_message": "Account cannot be created as it violates a duplicate constraint"}`
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		policy := DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.MaxDelay = time.Millisecond
+		client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(policy))
+	})
+
+	buildConflictResponse := func() *http.Response {
+		return &http.Response{
+			StatusCode: 409,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(conflictBody))),
+		}
+	}
+
+	It("sends a new request id for every request and the same one on retries", func() {
+		requestIDs := []string{}
+		recordRequestID := func(req *http.Request) {
+			requestIDs = append(requestIDs, req.Header.Get(RequestIDHeader))
+		}
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(gomock.Any()).Do(recordRequestID).Return(&http.Response{StatusCode: 503}, nil).Times(1),
+			httpClientMock.EXPECT().Do(gomock.Any()).Do(recordRequestID).Return(buildFetchResponse(), nil).Times(1),
+			httpClientMock.EXPECT().Do(gomock.Any()).Do(recordRequestID).Return(buildFetchResponse(), nil).Times(1),
+		)
+
+		client.Fetch(ctx, resources.Account, id)
+		client.Fetch(ctx, resources.Account, id)
+
+		_, err := uuid.Parse(requestIDs[0])
+		Expect(err).To(BeNil())
+		Expect(requestIDs[1]).To(Equal(requestIDs[0]))
+		Expect(requestIDs[2]).NotTo(Equal(requestIDs[0]))
+	})
+	It("sends the idempotency key header", func() {
+		account := BuildUKAccountWithCoP(id, organisationID)
+		httpClientMock.EXPECT().Do(IsRequestHeader(IdempotencyKeyHeader, "key-1")).Return(
+			buildResponse(201, resources.NewDataContainer(account)), nil,
+		).Times(1)
+
+		_, err := client.Create(ctx, resources.Account, account, WithIdempotencyKey("key-1"))
+
+		Expect(err).To(BeNil())
+	})
+	It("retries creates with an idempotency key", func() {
+		account := BuildUKAccountWithCoP(id, organisationID)
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(nil, errors.New("connection reset")).Times(1),
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(buildResponse(201, resources.NewDataContainer(account)), nil).Times(1),
+		)
+
+		response, err := client.Create(ctx, resources.Account, account, WithIdempotencyKey("key-1"))
+
+		Expect(err).To(BeNil())
+		Expect(response.Data.ID).To(Equal(id))
+	})
+	It("doesn't retry creates without an idempotency key", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection reset")).Times(1)
+
+		_, err := client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+		Expect(err).NotTo(BeNil())
+	})
+	It("returns the existing resource when it was already created with the same payload", func() {
+		account := BuildUKAccountWithCoP(id, organisationID)
+		existing := BuildUKAccountWithCoP(id, organisationID)
+		existing.Version = 0
+		existing.Attributes["status"] = "confirmed"
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(buildConflictResponse(), nil).Times(1),
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(existing)), nil,
+			).Times(1),
+		)
+
+		response, err := client.Create(ctx, resources.Account, account, WithIdempotencyKey("key-1"))
+
+		Expect(err).To(BeNil())
+		Expect(response.Data.ID).To(Equal(id))
+		Expect(response.Data.Attributes["status"]).To(Equal("confirmed"))
+	})
+	It("returns the ErrConflict error when the existing resource has a different payload", func() {
+		account := BuildUKAccountWithCoP(id, organisationID)
+		existing := BuildUKAccountWithCoP(id, organisationID)
+		existing.Attributes["bank_id"] = "400301"
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(buildConflictResponse(), nil).Times(1),
+			httpClientMock.EXPECT().Do(IsRequestMethod("GET")).Return(
+				buildResponse(200, resources.NewDataContainer(existing)), nil,
+			).Times(1),
+		)
+
+		response, err := client.Create(ctx, resources.Account, account, WithIdempotencyKey("key-1"))
+
+		Expect(response).To(BeNil())
+		Expect(errors.Is(err, ErrConflict{})).To(BeTrue())
+	})
+	It("returns the ErrConflict error without an idempotency key", func() {
+		httpClientMock.EXPECT().Do(IsRequestMethod("POST")).Return(buildConflictResponse(), nil).Times(1)
+
+		_, err := client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+		Expect(errors.Is(err, ErrConflict{})).To(BeTrue())
+	})
+})
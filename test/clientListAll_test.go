@@ -0,0 +1,126 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Account api resource client LIST ALL iterator", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		emptyFilter    map[string]interface{}
+		firstPageURL   = fmt.Sprintf("%s/organisation/accounts?page[number]=0&page[size]=1", baseURL)
+		secondPageURL  = "/v1/organisation/accounts?page[number]=1&page[size]=1"
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+	})
+
+	Context("When there are several pages", func() {
+		It("returns the resources of every page following the next links", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(firstPageURL)).Return(
+				buildListResponse(secondPageURL, BuildBasicAccountResource(id, organisationID)),
+				nil,
+			).Times(1)
+			httpClientMock.EXPECT().Do(IsRequestURL(secondPageURL)).Return(
+				buildListResponse("", BuildBasicAccountResource(id2, organisationID2)),
+				nil,
+			).Times(1)
+
+			it := client.ListAll(ctx, resources.Account, emptyFilter, 1)
+
+			ids := []string{}
+			for it.Next() {
+				ids = append(ids, it.Resource().ID)
+			}
+			Expect(it.Err()).To(BeNil())
+			Expect(ids).To(Equal([]string{id, id2}))
+		})
+		It("doesn't request the next page when the iteration stops early", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(firstPageURL)).Return(
+				buildListResponse(secondPageURL, BuildBasicAccountResource(id, organisationID)),
+				nil,
+			).Times(1)
+
+			it := client.ListAll(ctx, resources.Account, emptyFilter, 1)
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Resource().ID).To(Equal(id))
+		})
+		It("stops when the page is empty even if there is a next link", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(firstPageURL)).Return(
+				buildListResponse(secondPageURL),
+				nil,
+			).Times(1)
+
+			it := client.ListAll(ctx, resources.Account, emptyFilter, 1)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(BeNil())
+		})
+	})
+	Context("When something goes wrong", func() {
+		It("stops with the request error", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+			it := client.ListAll(ctx, resources.Account, emptyFilter, 1)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).NotTo(BeNil())
+		})
+		It("stops with the context error without requesting when the context is done", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			it := client.ListAll(cancelledCtx, resources.Account, emptyFilter, 1)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(Equal(context.Canceled))
+		})
+		It("stops with ErrInvalidFilterValue error when the filter is not valid", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+			filter := map[string]interface{}{"bank_id": struct{}{}}
+
+			it := client.ListAll(ctx, resources.Account, filter, 1)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).Should(MatchError(NewErrInvalidFilterValue("bank_id", struct{}{})))
+		})
+	})
+})
+
+func buildListResponse(nextLink string, data ...resources.Resource) *http.Response {
+	container := resources.ListDataContainer{
+		Data:  data,
+		Links: map[string]string{},
+	}
+	if nextLink != "" {
+		container.Links["next"] = nextLink
+	}
+	dataBt, _ := json.Marshal(container)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader(dataBt)),
+	}
+}
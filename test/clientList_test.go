@@ -54,6 +54,50 @@ var _ = Describe("Account api resource client LIST method", func() {
 
 			client.List(ctx, resources.Account, emptyFilter, pageNumber, pageSize)
 		})
+		It("builds a request with filter[name] parameters from the filter map", func() {
+			filter := map[string]interface{}{
+				"bank_id": "400300",
+				"country": "GB",
+			}
+			expectedFilterURL := fmt.Sprintf(
+				"%s/organisation/accounts?filter[bank_id]=400300&filter[country]=GB&page[number]=%d&page[size]=%d",
+				baseURL,
+				pageNumber,
+				pageSize,
+			)
+			httpClientMock.EXPECT().Do(IsRequestURL(expectedFilterURL)).Return(nil, errors.New("fake")).Times(1)
+
+			client.List(ctx, resources.Account, filter, pageNumber, pageSize)
+		})
+		It("builds a request with comma separated values when filter value is a slice", func() {
+			filter := map[string]interface{}{
+				"country": []string{"GB", "FR"},
+			}
+			expectedFilterURL := fmt.Sprintf(
+				"%s/organisation/accounts?filter[country]=GB,FR&page[number]=%d&page[size]=%d",
+				baseURL,
+				pageNumber,
+				pageSize,
+			)
+			httpClientMock.EXPECT().Do(IsRequestURL(expectedFilterURL)).Return(nil, errors.New("fake")).Times(1)
+
+			client.List(ctx, resources.Account, filter, pageNumber, pageSize)
+		})
+		It("builds a request with escaped filter values", func() {
+			filter := map[string]interface{}{
+				"account_number": "41426819 & co,1",
+				"joint_account":  false,
+			}
+			expectedFilterURL := fmt.Sprintf(
+				"%s/organisation/accounts?filter[account_number]=41426819+%%26+co%%2C1&filter[joint_account]=false&page[number]=%d&page[size]=%d",
+				baseURL,
+				pageNumber,
+				pageSize,
+			)
+			httpClientMock.EXPECT().Do(IsRequestURL(expectedFilterURL)).Return(nil, errors.New("fake")).Times(1)
+
+			client.List(ctx, resources.Account, filter, pageNumber, pageSize)
+		})
 	})
 	Context("When getting succesful response", func() {
 		It("returns ListDataContainer struct as response data", func() {
@@ -109,6 +153,20 @@ var _ = Describe("Account api resource client LIST method", func() {
 			Expect(response).To(BeNil())
 			Expect(err).NotTo(BeNil())
 		})
+		It("returns ErrInvalidFilterValue error without requesting when a filter value type is not supported", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+			filter := map[string]interface{}{
+				"bank_id": struct{}{},
+			}
+
+			response, err := client.List(ctx, resources.Account, filter, pageNumber, pageSize)
+
+			Expect(response).To(BeNil())
+			Expect(err).Should(
+				MatchError(
+					NewErrInvalidFilterValue("bank_id", struct{}{})),
+			)
+		})
 	})
 	Context("When getting error response from the server", func() {
 		It("returns an error when server responses an error 50X", func() {
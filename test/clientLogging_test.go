@@ -0,0 +1,145 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+type recordingLogger struct {
+	records []logRecord
+}
+
+func (l *recordingLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.record("info", msg, args)
+}
+
+func (l *recordingLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.record("error", msg, args)
+}
+
+func (l *recordingLogger) record(level, msg string, args []interface{}) {
+	attrs := map[string]interface{}{}
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+	l.records = append(l.records, logRecord{level, msg, attrs})
+}
+
+var _ = Describe("Client request logging", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		logger         *recordingLogger
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		logger = &recordingLogger{}
+	})
+
+	It("logs the method, url, status, latency and request id", func() {
+		client := NewForm3APIClient(baseURL, httpClientMock, WithLogger(logger, DefaultLoggingOptions()))
+		response := buildFetchResponse()
+		response.Header = http.Header{}
+		response.Header.Set(RequestIDHeader, "request-1")
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(response, nil).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		Expect(logger.records).To(HaveLen(1))
+		record := logger.records[0]
+		Expect(record.level).To(Equal("info"))
+		Expect(record.attrs).To(HaveKeyWithValue("method", "GET"))
+		Expect(record.attrs).To(HaveKeyWithValue("url", baseURL+"/organisation/accounts/"+id))
+		Expect(record.attrs).To(HaveKeyWithValue("status", 200))
+		Expect(record.attrs).To(HaveKeyWithValue("request_id", "request-1"))
+		Expect(record.attrs).To(HaveKeyWithValue("operation", "fetch"))
+		Expect(record.attrs).To(HaveKey("latency"))
+		Expect(record.attrs).NotTo(HaveKey("response_body"))
+	})
+	It("logs failed requests as errors", func() {
+		client := NewForm3APIClient(baseURL, httpClientMock, WithLogger(logger, DefaultLoggingOptions()))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection reset")).Times(1)
+
+		client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+		Expect(logger.records[0].level).To(Equal("error"))
+		Expect(logger.records[0].attrs).To(HaveKeyWithValue("error", "connection reset"))
+	})
+	It("logs the bodies masking the personal data", func() {
+		options := DefaultLoggingOptions()
+		options.LogBodies = true
+		client := NewForm3APIClient(baseURL, httpClientMock, WithLogger(logger, options))
+		account := BuildUKAccountWithCoP(id, organisationID)
+		account.Attributes["iban"] = "GB33BUKB20201555555555"
+		responseBody, _ := json.Marshal(resources.NewDataContainer(account))
+		httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			requestBody, err := ioutil.ReadAll(req.Body)
+			Expect(err).To(BeNil())
+			Expect(string(requestBody)).To(ContainSubstring("GB33BUKB20201555555555"))
+			return &http.Response{
+				StatusCode: 201,
+				Body:       ioutil.NopCloser(bytes.NewReader(responseBody)),
+			}, nil
+		}).Times(1)
+
+		response, err := client.Create(ctx, resources.Account, account)
+
+		Expect(err).To(BeNil())
+		Expect(response.Data.Attributes["iban"]).To(Equal("GB33BUKB20201555555555"))
+		for _, key := range []string{"request_body", "response_body"} {
+			body := logger.records[0].attrs[key].(string)
+			Expect(body).To(ContainSubstring(`"iban":"[REDACTED]"`))
+			Expect(body).To(ContainSubstring(`"name":"[REDACTED]"`))
+			Expect(body).To(ContainSubstring(`"country":"GB"`))
+			Expect(body).NotTo(ContainSubstring("GB33BUKB20201555555555"))
+		}
+	})
+	It("masks the filters on personal data in the url", func() {
+		client := NewForm3APIClient(baseURL, httpClientMock, WithLogger(logger, DefaultLoggingOptions()))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildListResponse(""), nil).Times(1)
+
+		client.List(ctx, resources.Account, map[string]interface{}{"account_number": "41426819", "country": "GB"}, pageNumber, pageSize)
+
+		url := logger.records[0].attrs["url"].(string)
+		Expect(url).To(ContainSubstring("filter[account_number]=%5BREDACTED%5D"))
+		Expect(url).To(ContainSubstring("filter[country]=GB"))
+		Expect(url).NotTo(ContainSubstring("41426819"))
+	})
+	It("works with a slog logger", func() {
+		var output bytes.Buffer
+		slogger := slog.New(slog.NewJSONHandler(&output, nil))
+		client := NewForm3APIClient(baseURL, httpClientMock, WithLogger(slogger, DefaultLoggingOptions()))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+
+		client.Delete(ctx, resources.Account, id, version)
+
+		record := map[string]interface{}{}
+		Expect(json.Unmarshal(output.Bytes(), &record)).To(Succeed())
+		Expect(record).To(HaveKeyWithValue("method", "DELETE"))
+		Expect(record).To(HaveKeyWithValue("status", BeNumerically("==", 204)))
+	})
+})
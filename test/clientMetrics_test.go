@@ -0,0 +1,113 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/metrics"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client metrics", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		registry       *prometheus.Registry
+		client         *Form3Client
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		registry = prometheus.NewRegistry()
+		recorder, err := metrics.NewPrometheusRecorder(registry)
+		Expect(err).To(BeNil())
+		policy := DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.MaxDelay = time.Millisecond
+		client = NewForm3APIClient(baseURL, httpClientMock, WithMetrics(recorder), WithRetryPolicy(policy))
+	})
+
+	// counterValue returns the value of the counter with the labels.
+	counterValue := func(name string, labels map[string]string) float64 {
+		families, err := registry.Gather()
+		Expect(err).To(BeNil())
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				metricLabels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					metricLabels[label.GetName()] = label.GetValue()
+				}
+				if reflect.DeepEqual(metricLabels, labels) {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+		return 0
+	}
+	requests := func(operation Operation, statusClass string) float64 {
+		return counterValue("form3_client_requests_total", map[string]string{
+			"resource":     "account",
+			"operation":    string(operation),
+			"status_class": statusClass,
+		})
+	}
+
+	It("counts the requests by resource, operation and status class", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+		client.Fetch(ctx, resources.Account, id)
+		client.Delete(ctx, resources.Account, id, version)
+
+		Expect(requests(OperationFetch, "2xx")).To(Equal(1.0))
+		Expect(requests(OperationFetch, "4xx")).To(Equal(1.0))
+		Expect(requests(OperationDelete, "2xx")).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(registry, "form3_client_request_duration_seconds")).To(Equal(3))
+		Expect(testutil.CollectAndCount(registry, "form3_client_errors_total")).To(Equal(1))
+	})
+	It("counts the retries and the final status of the retried request once", func() {
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1),
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection reset")).Times(1),
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+		)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		Expect(requests(OperationFetch, "2xx")).To(Equal(1.0))
+		Expect(requests(OperationFetch, "5xx")).To(Equal(0.0))
+		Expect(counterValue("form3_client_retries_total", map[string]string{"resource": "account", "operation": "fetch"})).To(Equal(2.0))
+	})
+	It("uses the error status class for requests without response", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+		client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+		Expect(requests(OperationCreate, StatusClassError)).To(Equal(1.0))
+	})
+	It("returns an error when the metrics are already registered", func() {
+		_, err := metrics.NewPrometheusRecorder(registry)
+
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,137 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client middleware chain", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+	})
+
+	recordingMiddleware := func(name string, calls *[]string) Middleware {
+		return func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next(req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		}
+	}
+
+	It("runs the middlewares in the registration order around the request", func() {
+		calls := []string{}
+		client := NewForm3APIClient(baseURL, httpClientMock,
+			WithMiddleware(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls)),
+			WithMiddleware(recordingMiddleware("third", &calls)),
+		)
+		httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "request")
+			return buildFetchResponse(), nil
+		}).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		Expect(calls).To(Equal([]string{
+			"first before", "second before", "third before",
+			"request",
+			"third after", "second after", "first after",
+		}))
+	})
+	It("gives the resource name, operation and id of every request", func() {
+		infos := []RequestInfo{}
+		client := NewForm3APIClient(baseURL, httpClientMock, WithMiddleware(
+			func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+				infos = append(infos, info)
+				return next(req)
+			},
+		))
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildListResponse(""), nil).Times(2)
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 204}, nil).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+		client.List(ctx, resources.Account, nil, pageNumber, pageSize)
+		it := client.ListAll(ctx, resources.Account, nil, pageSize)
+		for it.Next() {
+		}
+		client.Delete(ctx, resources.Account, id, version)
+
+		Expect(infos).To(Equal([]RequestInfo{
+			{ResourceName: resources.Account, Operation: OperationFetch, ResourceID: id},
+			{ResourceName: resources.Account, Operation: OperationList},
+			{ResourceName: resources.Account, Operation: OperationList},
+			{ResourceName: resources.Account, Operation: OperationDelete, ResourceID: id},
+		}))
+	})
+	It("sends the request changed by a middleware", func() {
+		client := NewForm3APIClient(baseURL, httpClientMock, WithMiddleware(
+			func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+				req.Header.Set("X-Team", "platform")
+				return next(req)
+			},
+		))
+		httpClientMock.EXPECT().Do(IsRequestHeader("X-Team", "platform")).Return(buildFetchResponse(), nil).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+	})
+	It("short-circuits the chain when a middleware doesn't call next", func() {
+		calls := []string{}
+		shortCircuitErr := errors.New("blocked")
+		client := NewForm3APIClient(baseURL, httpClientMock, WithMiddleware(
+			recordingMiddleware("first", &calls),
+			func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+				return nil, shortCircuitErr
+			},
+			recordingMiddleware("third", &calls),
+		))
+		httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(Equal(shortCircuitErr))
+		Expect(calls).To(Equal([]string{"first before", "first after"}))
+	})
+	It("runs the chain for every retry attempt", func() {
+		attempts := 0
+		policy := DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.MaxDelay = time.Millisecond
+		client := NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(policy), WithMiddleware(
+			func(info RequestInfo, req *http.Request, next RequestHandler) (*http.Response, error) {
+				attempts++
+				return next(req)
+			},
+		))
+		gomock.InOrder(
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1),
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+		)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		Expect(attempts).To(Equal(2))
+	})
+})
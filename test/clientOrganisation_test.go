@@ -0,0 +1,150 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+	"github.com/regiluze/form3-account-api-client/validation"
+)
+
+var _ = Describe("Organisation units", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+		unitsURL       = fmt.Sprintf("%s/organisation/units", baseURL)
+		organisation   = resources.OrganisationResource{
+			ID:             id,
+			OrganisationID: organisationID,
+			Attributes: resources.OrganisationAttributes{
+				Name:    "Samantha Holder Ltd",
+				Address: []string{"10 Acme Street"},
+				City:    "London",
+				Country: "GB",
+			},
+		}
+		organisationData = resources.NewOrganisation(id, organisationID, map[string]interface{}{
+			"name":    "Samantha Holder Ltd",
+			"address": []interface{}{"10 Acme Street"},
+			"city":    "London",
+			"country": "GB",
+		})
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+	})
+
+	Context("Requesting the organisation units endpoint", func() {
+		It("creates an organisation unit", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(unitsURL)).Return(
+				buildResponse(201, resources.NewDataContainer(organisationData)), nil,
+			).Times(1)
+
+			created, err := CreateOrganisation(ctx, client, organisation)
+
+			Expect(err).To(BeNil())
+			Expect(*created).To(Equal(organisation))
+		})
+		It("sends the organisation unit with the organisations type", func() {
+			var sent resources.DataContainer
+			httpClientMock.EXPECT().Do(IsRequestMethod("POST")).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(req.Body)
+				json.Unmarshal(body, &sent)
+				return buildResponse(201, resources.NewDataContainer(organisationData)), nil
+			}).Times(1)
+
+			_, err := CreateOrganisation(ctx, client, organisation)
+
+			Expect(err).To(BeNil())
+			Expect(sent.Data).To(Equal(organisationData))
+		})
+		It("fetches an organisation unit", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", unitsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(organisationData)), nil,
+			).Times(1)
+
+			fetched, err := FetchOrganisation(ctx, client, id)
+
+			Expect(err).To(BeNil())
+			Expect(*fetched).To(Equal(organisation))
+		})
+		It("returns ErrNotFound error when the organisation unit doesn't exist", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", unitsURL, id))).Return(
+				&http.Response{StatusCode: 404}, nil,
+			).Times(1)
+
+			_, err := FetchOrganisation(ctx, client, id)
+
+			Expect(errors.Is(err, ErrNotFound{})).To(BeTrue())
+		})
+		It("lists the organisation units matching the filter", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s?filter[country]=GB&page[number]=0&page[size]=10", unitsURL))).Return(
+				buildResponse(200, resources.ListDataContainer{Data: []resources.Resource{organisationData}}), nil,
+			).Times(1)
+
+			page, err := ListOrganisations(ctx, client, map[string]interface{}{"country": "GB"}, 0, 10)
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]resources.OrganisationResource{organisation}))
+		})
+		It("deletes an organisation unit version", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s?version=%d", unitsURL, id, version))).Return(
+				&http.Response{StatusCode: 204}, nil,
+			).Times(1)
+
+			err := DeleteOrganisation(ctx, client, id, version)
+
+			Expect(err).To(BeNil())
+		})
+		It("requests the organisation units with the generic client", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", unitsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(organisationData)), nil,
+			).Times(1)
+
+			response, err := client.Fetch(ctx, resources.Organisation, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ResourceType).To(Equal(resources.OrganisationType))
+		})
+	})
+	Context("Validating organisation units", func() {
+		It("accepts a valid organisation unit", func() {
+			Expect(validation.Validate(resources.Organisation, organisationData)).To(BeNil())
+		})
+		It("returns errors for the missing name and the invalid ids and country", func() {
+			err := validation.Validate(resources.Organisation, resources.NewOrganisation("1", "2", map[string]interface{}{
+				"country": "United Kingdom",
+			}))
+
+			Expect(err).To(Equal(validation.Errors{
+				{Field: "id", Rule: validation.RuleFormat, Message: "id must be a uuid"},
+				{Field: "organisation_id", Rule: validation.RuleFormat, Message: "organisation_id must be a uuid"},
+				{Field: "attributes.name", Rule: validation.RuleRequired, Message: "name is required"},
+				{Field: "attributes.country", Rule: validation.RuleFormat, Message: "country must be an ISO 3166-1 code"},
+			}))
+		})
+		It("returns an error when the name is too long", func() {
+			errs := validation.ValidateOrganisationAttributes(resources.OrganisationAttributes{Name: strings.Repeat("a", 141)})
+
+			Expect(errs.Field("attributes.name")).To(HaveLen(1))
+			Expect(errs.Field("attributes.name")[0].Rule).To(Equal(validation.RuleMaxLength))
+		})
+	})
+})
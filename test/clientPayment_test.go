@@ -0,0 +1,190 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+	"github.com/regiluze/form3-account-api-client/validation"
+)
+
+var _ = Describe("Payments", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+		paymentsURL    = fmt.Sprintf("%s/transaction/payments", baseURL)
+		payment        = BuildPaymentResource(id, organisationID)
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+	})
+
+	paymentData := func() resources.Resource {
+		resource, err := payment.Resource()
+		Expect(err).To(BeNil())
+		return resource
+	}
+
+	Context("Payment model", func() {
+		It("converts a payment into a generic resource keeping the amount as a decimal string", func() {
+			resource := paymentData()
+
+			Expect(resource.ResourceType).To(Equal(resources.PaymentType))
+			Expect(resource.Attributes["amount"]).To(Equal("100.10"))
+			Expect(resource.Attributes["beneficiary_party"]).To(HaveKeyWithValue("account_number", "31926819"))
+		})
+		It("builds a payment party from an account with an account number", func() {
+			account := BuildUKAccountResourceWithCoP(id, organisationID)
+			account.Attributes.AccountNumber = "41426819"
+
+			party := resources.NewPaymentParty(account)
+
+			Expect(party).To(Equal(resources.PaymentParty{
+				AccountName:       "Samantha Holder",
+				AccountNumber:     "41426819",
+				AccountNumberCode: resources.AccountNumberCodeBBAN,
+				BankID:            "400300",
+				BankIDCode:        "GBDSC",
+				Name:              "Samantha Holder",
+			}))
+		})
+		It("builds a payment party from an account with only an IBAN", func() {
+			account := BuildUKAccountResourceWithCoP(id, organisationID)
+			account.Attributes.Iban = "GB11NWBK40030041426819"
+
+			party := resources.NewPaymentParty(account)
+
+			Expect(party.AccountNumber).To(Equal("GB11NWBK40030041426819"))
+			Expect(party.AccountNumberCode).To(Equal(resources.AccountNumberCodeIBAN))
+		})
+	})
+	Context("Requesting the payments endpoint", func() {
+		It("creates a payment", func() {
+			var sent resources.DataContainer
+			httpClientMock.EXPECT().Do(IsRequestURL(paymentsURL)).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(req.Body)
+				json.Unmarshal(body, &sent)
+				return buildResponse(201, resources.NewDataContainer(paymentData())), nil
+			}).Times(1)
+
+			created, err := CreatePayment(ctx, client, payment)
+
+			Expect(err).To(BeNil())
+			Expect(*created).To(Equal(payment))
+			Expect(sent.Data.ResourceType).To(Equal(resources.PaymentType))
+			Expect(sent.Data.Attributes["amount"]).To(Equal("100.10"))
+		})
+		It("fetches a payment", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", paymentsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(paymentData())), nil,
+			).Times(1)
+
+			fetched, err := FetchPayment(ctx, client, id)
+
+			Expect(err).To(BeNil())
+			Expect(*fetched).To(Equal(payment))
+		})
+		It("lists the payments matching the filter", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s?filter[currency]=GBP&page[number]=1&page[size]=20", paymentsURL))).Return(
+				buildResponse(200, resources.ListDataContainer{Data: []resources.Resource{paymentData()}}), nil,
+			).Times(1)
+
+			page, err := ListPayments(ctx, client, map[string]interface{}{"currency": "GBP"}, 1, 20)
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]resources.PaymentResource{payment}))
+		})
+		It("requests the payments with the generic client", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", paymentsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(paymentData())), nil,
+			).Times(1)
+
+			response, err := client.Fetch(ctx, resources.Payment, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ResourceType).To(Equal(resources.PaymentType))
+		})
+		It("fetches an account to use it as a payment party", func() {
+			account := BuildUKAccountWithCoP(id2, organisationID)
+			account.Attributes["account_number"] = "41426819"
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id2))).Return(
+				buildResponse(200, resources.NewDataContainer(account)), nil,
+			).Times(1)
+
+			party, err := FetchPaymentParty(ctx, client, id2)
+
+			Expect(err).To(BeNil())
+			Expect(party.AccountNumber).To(Equal("41426819"))
+			Expect(party.BankID).To(Equal("400300"))
+			Expect(party.Name).To(Equal("Samantha Holder"))
+		})
+		It("returns the account error when the party account doesn't exist", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+
+			party, err := FetchPaymentParty(ctx, client, id2)
+
+			Expect(party).To(BeNil())
+			Expect(errors.Is(err, ErrNotFound{})).To(BeTrue())
+		})
+	})
+	Context("Validating payments", func() {
+		It("accepts a valid payment", func() {
+			Expect(validation.Validate(resources.Payment, paymentData())).To(BeNil())
+		})
+		It("returns errors for the required attributes", func() {
+			err := validation.Validate(resources.Payment, resources.NewPayment(id, organisationID, map[string]interface{}{}))
+
+			Expect(err).To(Equal(validation.Errors{
+				{Field: "attributes.amount", Rule: validation.RuleRequired, Message: "amount is required"},
+				{Field: "attributes.currency", Rule: validation.RuleRequired, Message: "currency is required"},
+				{Field: "attributes.beneficiary_party.account_number", Rule: validation.RuleRequired, Message: "beneficiary_party.account_number is required"},
+				{Field: "attributes.debtor_party.account_number", Rule: validation.RuleRequired, Message: "debtor_party.account_number is required"},
+			}))
+		})
+		It("returns format errors for the amount, currency, processing date and IBAN", func() {
+			attributes := payment.Attributes
+			attributes.Amount = "100,10"
+			attributes.Currency = "pounds"
+			attributes.ProcessingDate = "18/01/2021"
+			attributes.DebtorParty.AccountNumber = "not an iban"
+
+			errs := validation.ValidatePaymentAttributes(attributes)
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.amount", Rule: validation.RuleFormat, Message: "amount must be a positive decimal number"},
+				{Field: "attributes.currency", Rule: validation.RuleFormat, Message: "currency must be an ISO 4217 code"},
+				{Field: "attributes.processing_date", Rule: validation.RuleFormat, Message: "processing_date must be a YYYY-MM-DD date"},
+				{Field: "attributes.debtor_party.account_number", Rule: validation.RuleFormat, Message: "debtor_party.account_number must be an IBAN"},
+			}))
+		})
+		It("rejects zero amounts and accepts amounts under one", func() {
+			attributes := payment.Attributes
+			for _, amount := range []string{"0", "0.00", "00.0"} {
+				attributes.Amount = amount
+
+				Expect(validation.ValidatePaymentAttributes(attributes)).To(Equal(validation.Errors{
+					{Field: "attributes.amount", Rule: validation.RuleFormat, Message: "amount must be a positive decimal number"},
+				}), amount)
+			}
+			attributes.Amount = "0.01"
+
+			Expect(validation.ValidatePaymentAttributes(attributes)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,150 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client rate limiting", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		expectedURL    = fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id)
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+	})
+
+	newRateLimiter := func(rate float64, burst int) *RateLimiter {
+		limiter, err := NewRateLimiter(rate, burst)
+		Expect(err).To(BeNil())
+		return limiter
+	}
+	buildRateLimitedResponse := func(retryAfter string) *http.Response {
+		header := http.Header{}
+		header.Set("Retry-After", retryAfter)
+		header.Set(RateLimitLimitHeader, "100")
+		header.Set(RateLimitRemainingHeader, "0")
+		header.Set(RateLimitResetHeader, "1700000000")
+		return &http.Response{StatusCode: 429, Header: header}
+	}
+
+	Context("429 responses", func() {
+		It("returns an ErrRateLimited error with the Retry-After and rate limit headers", func() {
+			client := NewForm3APIClient(baseURL, httpClientMock)
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(buildRateLimitedResponse("2"), nil).Times(1)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).Should(MatchError(NewErrRateLimited("GET", expectedURL, 2*time.Second, 100, 0, "1700000000")))
+			var rateLimited ErrRateLimited
+			Expect(errors.As(err, &rateLimited)).To(BeTrue())
+			Expect(rateLimited.RetryAfter()).To(Equal(2 * time.Second))
+			Expect(rateLimited.Limit()).To(Equal(100))
+			Expect(rateLimited.Remaining()).To(Equal(0))
+			Expect(rateLimited.Reset()).To(Equal("1700000000"))
+			Expect(errors.Is(err, ErrResponseStatusCode{StatusCode: 429})).To(BeTrue())
+		})
+		It("retries after the Retry-After time with the default retry policy", func() {
+			client := NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(DefaultRetryPolicy()))
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildRateLimitedResponse("0"), nil).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("pauses the requests of the rate limiter for the Retry-After time", func() {
+			client := NewForm3APIClient(baseURL, httpClientMock, WithRateLimiter(newRateLimiter(1000, 10)))
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(buildRateLimitedResponse("1"), nil).Times(1)
+			client.Fetch(ctx, resources.Account, id)
+			timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+
+			_, err := client.Fetch(timeoutCtx, resources.Account, id)
+
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+	Context("Token bucket", func() {
+		It("lets the burst through and then limits the rate", func() {
+			client := NewForm3APIClient(baseURL, httpClientMock, WithRateLimiter(newRateLimiter(20, 2)))
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return buildFetchResponse(), nil
+			}).Times(4)
+
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				_, err := client.Fetch(ctx, resources.Account, id)
+				Expect(err).To(BeNil())
+			}
+
+			Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+		})
+		It("limits the requests of all the goroutines together", func() {
+			limiter := newRateLimiter(100, 1)
+			client := NewForm3APIClient(baseURL, httpClientMock, WithRateLimiter(limiter))
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return buildFetchResponse(), nil
+			}).Times(10)
+
+			start := time.Now()
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					client.Fetch(ctx, resources.Account, id)
+				}()
+			}
+			wg.Wait()
+
+			Expect(time.Since(start)).To(BeNumerically(">=", 80*time.Millisecond))
+		})
+		It("returns the context error when it's done while waiting", func() {
+			limiter := newRateLimiter(1, 1)
+			Expect(limiter.Wait(ctx)).To(Succeed())
+			timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+
+			Expect(limiter.Wait(timeoutCtx)).To(MatchError(context.DeadlineExceeded))
+		})
+		It("waits for the pause", func() {
+			limiter := newRateLimiter(1000, 10)
+			limiter.Pause(50 * time.Millisecond)
+
+			start := time.Now()
+			Expect(limiter.Wait(ctx)).To(Succeed())
+
+			Expect(time.Since(start)).To(BeNumerically(">=", 45*time.Millisecond))
+		})
+		It("returns ErrInvalidConfig error when the rate isn't positive", func() {
+			_, err := NewRateLimiter(0, 1)
+
+			Expect(err).Should(MatchError(NewErrInvalidConfig("rate", "must be greater than 0")))
+		})
+		It("returns ErrInvalidConfig error when the burst is lower than 1", func() {
+			_, err := NewRateLimiter(10, 0)
+
+			Expect(err).Should(MatchError(NewErrInvalidConfig("burst", "must be at least 1")))
+		})
+	})
+})
@@ -0,0 +1,231 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client retry policy", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		policy         RetryPolicy
+		expectedURL    = fmt.Sprintf("%s/organisation/accounts", baseURL)
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		policy = DefaultRetryPolicy()
+		policy.BaseDelay = time.Millisecond
+		policy.MaxDelay = time.Millisecond
+		policy.Jitter = 0
+		client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(policy))
+	})
+
+	Context("When the request is retryable", func() {
+		It("retries when server responses a retryable status code", func() {
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+		})
+		It("retries when http client returns an error", func() {
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection reset")).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+		})
+		It("returns the last error when all the attempts fail", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(policy.MaxAttempts)
+
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(response).To(BeNil())
+			Expect(err).Should(
+				MatchError(
+					NewErrResponseStatusCode("GET", fmt.Sprintf("%s/%s", expectedURL, id), 503)),
+			)
+		})
+		It("resends the request body when POST is a retryable method", func() {
+			policy.RetryableMethods = append(policy.RetryableMethods, http.MethodPost)
+			client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(policy))
+			accountData := BuildBasicAccountResource(id, organisationID)
+			expectedBody, _ := json.Marshal(resources.NewDataContainer(accountData))
+			bodies := [][]byte{}
+			readBody := func(req *http.Request) {
+				body, _ := ioutil.ReadAll(req.Body)
+				bodies = append(bodies, body)
+			}
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					readBody(req)
+					return &http.Response{StatusCode: 502}, nil
+				}).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					readBody(req)
+					return buildFetchResponse(), nil
+				}).Times(1),
+			)
+
+			_, err := client.Create(ctx, resources.Account, accountData)
+
+			Expect(err).To(BeNil())
+			Expect(bodies).To(Equal([][]byte{expectedBody, expectedBody}))
+		})
+	})
+	Context("When the request is not retryable", func() {
+		It("doesn't retry POST requests by default", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1)
+
+			_, err := client.Create(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+
+			Expect(err).Should(
+				MatchError(NewErrResponseStatusCode("POST", expectedURL, 503)),
+			)
+		})
+		It("doesn't retry status codes out of the policy", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).NotTo(BeNil())
+		})
+		It("doesn't retry when Retry-After header exceeds the context deadline", func() {
+			deadlineCtx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 503,
+					Header:     http.Header{"Retry-After": []string{"120"}},
+				},
+				nil,
+			).Times(1)
+
+			_, err := client.Fetch(deadlineCtx, resources.Account, id)
+
+			Expect(err).Should(
+				MatchError(NewErrResponseStatusCode("GET", fmt.Sprintf("%s/%s", expectedURL, id), 503)),
+			)
+		})
+		It("doesn't retry when Retry-After header exceeds the max delay", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 503,
+					Header:     http.Header{"Retry-After": []string{"1"}},
+				},
+				nil,
+			).Times(1)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).Should(
+				MatchError(NewErrResponseStatusCode("GET", fmt.Sprintf("%s/%s", expectedURL, id), 503)),
+			)
+		})
+	})
+	Context("When the policy leaves settings out", func() {
+		It("backs off up to the default max delay when MaxDelay is zero", func() {
+			client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(RetryPolicy{
+				MaxAttempts:          3,
+				BaseDelay:            20 * time.Millisecond,
+				RetryableStatusCodes: []int{503},
+				RetryableMethods:     []string{http.MethodGet},
+			}))
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(3)
+
+			start := time.Now()
+			client.Fetch(ctx, resources.Account, id)
+
+			Expect(time.Since(start)).To(BeNumerically(">=", 60*time.Millisecond))
+		})
+		It("respects Retry-After up to the default max delay when MaxDelay is zero", func() {
+			client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(RetryPolicy{
+				MaxAttempts:          2,
+				RetryableStatusCodes: []int{503},
+				RetryableMethods:     []string{http.MethodGet},
+			}))
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(
+					&http.Response{
+						StatusCode: 503,
+						Header:     http.Header{"Retry-After": []string{"0"}},
+					},
+					nil,
+				).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("sends a single attempt when MaxAttempts is zero", func() {
+			client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(RetryPolicy{
+				RetryableStatusCodes: []int{503},
+				RetryableMethods:     []string{http.MethodGet},
+			}))
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 503}, nil).Times(1)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("When the server sends a Retry-After header", func() {
+		It("waits the Retry-After time when it doesn't exceed the max delay", func() {
+			policy.MaxDelay = time.Second
+			client = NewForm3APIClient(baseURL, httpClientMock, WithRetryPolicy(policy))
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(
+					&http.Response{
+						StatusCode: 503,
+						Header:     http.Header{"Retry-After": []string{"0"}},
+					},
+					nil,
+				).Times(1),
+				httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+func buildFetchResponse() *http.Response {
+	data := resources.NewDataContainer(BuildBasicAccountResource(id, organisationID))
+	dataBt, _ := json.Marshal(data)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader(dataBt)),
+	}
+}
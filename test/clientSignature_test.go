@@ -0,0 +1,137 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const signatureKeyID = "75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8"
+
+// staticTokenSource always returns the same token.
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+func (s staticTokenSource) Invalidate(token string) {}
+
+var _ = Describe("Client HTTP signature authentication", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		rsaKey         *rsa.PrivateKey
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+		signer, err := NewHTTPSigner(signatureKeyID, rsaKey)
+		Expect(err).To(BeNil())
+		client = NewForm3APIClient("https://api.form3.tech/v1", httpClientMock, WithHTTPSigner(signer))
+	})
+
+	Context("Signing requests", func() {
+		It("signs the request with the key id and the signed headers", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				authorization := req.Header.Get("Authorization")
+				Expect(authorization).To(HavePrefix(`Signature keyId="` + signatureKeyID + `",algorithm="rsa-sha256"`))
+				Expect(authorization).To(ContainSubstring(`headers="(request-target) host date digest"`))
+				Expect(req.Header.Get("Date")).NotTo(BeEmpty())
+				Expect(VerifyHTTPSignature(req, &rsaKey.PublicKey)).To(Succeed())
+				return nil, errors.New("fake")
+			}).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+		})
+		It("signs the request body digest so the body can't be tampered", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Digest")).To(HavePrefix("SHA-256="))
+				Expect(VerifyHTTPSignature(req, &rsaKey.PublicKey)).To(Succeed())
+
+				req.GetBody = nil
+				req.Body = http.NoBody
+				Expect(VerifyHTTPSignature(req, &rsaKey.PublicKey)).NotTo(Succeed())
+				return nil, errors.New("fake")
+			}).Times(1)
+
+			client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+		})
+		It("signs requests with ECDSA keys", func() {
+			ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			signer, err := NewHTTPSigner(signatureKeyID, ecdsaKey)
+			Expect(err).To(BeNil())
+			client = NewForm3APIClient("https://api.form3.tech/v1", httpClientMock, WithHTTPSigner(signer))
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(req.Header.Get("Authorization")).To(ContainSubstring(`algorithm="ecdsa-sha256"`))
+				Expect(VerifyHTTPSignature(req, &ecdsaKey.PublicKey)).To(Succeed())
+				return nil, errors.New("fake")
+			}).Times(1)
+
+			client.Delete(ctx, resources.Account, id, version)
+		})
+		It("fails verification with another key", func() {
+			otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(VerifyHTTPSignature(req, &otherKey.PublicKey)).NotTo(Succeed())
+				return nil, errors.New("fake")
+			}).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+		})
+	})
+	Context("Combining with a token source", func() {
+		It("sends only the bearer token when the token source is set after the signer", func() {
+			signer, _ := NewHTTPSigner(signatureKeyID, rsaKey)
+			client = NewForm3APIClient("https://api.form3.tech/v1", httpClientMock, WithHTTPSigner(signer), WithTokenSource(staticTokenSource("token")))
+			httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token")).Return(nil, errors.New("fake")).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+		})
+		It("sends only the signature when the signer is set after the token source", func() {
+			signer, _ := NewHTTPSigner(signatureKeyID, rsaKey)
+			client = NewForm3APIClient("https://api.form3.tech/v1", httpClientMock, WithTokenSource(staticTokenSource("token")), WithHTTPSigner(signer))
+			httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				Expect(VerifyHTTPSignature(req, &rsaKey.PublicKey)).To(Succeed())
+				return nil, errors.New("fake")
+			}).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+		})
+	})
+	Context("Building the signer", func() {
+		It("returns an error when the key type is not supported", func() {
+			_, key, _ := ed25519.GenerateKey(rand.Reader)
+
+			signer, err := NewHTTPSigner(signatureKeyID, key)
+
+			Expect(signer).To(BeNil())
+			Expect(err).NotTo(BeNil())
+		})
+		It("returns an error when the key id is empty", func() {
+			signer, err := NewHTTPSigner("", rsaKey)
+
+			Expect(signer).To(BeNil())
+			Expect(strings.Contains(err.Error(), "key ID")).To(BeTrue())
+		})
+	})
+})
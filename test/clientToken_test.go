@@ -0,0 +1,181 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const (
+	clientID     = "client-id"
+	clientSecret = "client-secret"
+)
+
+var _ = Describe("Client OAuth2 client credentials token source", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		tokenServer    *httptest.Server
+		tokenRequests  int32
+		expiresIn      int
+		tokenDelay     time.Duration
+		tokenSource    *ClientCredentialsTokenSource
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		atomic.StoreInt32(&tokenRequests, 0)
+		expiresIn = 3600
+		tokenDelay = 0
+		tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || user != clientID || password != clientSecret || r.FormValue("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			n := atomic.AddInt32(&tokenRequests, 1)
+			time.Sleep(tokenDelay)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": fmt.Sprintf("token-%d", n),
+				"token_type":   "bearer",
+				"expires_in":   expiresIn,
+			})
+		}))
+		tokenSource = NewClientCredentialsTokenSource(
+			ClientCredentials{
+				TokenURL:     tokenServer.URL,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+			},
+			http.DefaultClient,
+		)
+		client = NewForm3APIClient(baseURL, httpClientMock, WithTokenSource(tokenSource))
+	})
+
+	AfterEach(func() {
+		tokenServer.Close()
+	})
+
+	Context("Authenticating requests", func() {
+		It("sends the token as a bearer token", func() {
+			httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-1")).Return(nil, errors.New("fake")).Times(1)
+
+			client.Fetch(ctx, resources.Account, id)
+		})
+		It("reuses the cached token while it's valid", func() {
+			httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-1")).Return(nil, errors.New("fake")).Times(2)
+
+			client.Fetch(ctx, resources.Account, id)
+			client.Fetch(ctx, resources.Account, id)
+
+			Expect(atomic.LoadInt32(&tokenRequests)).To(Equal(int32(1)))
+		})
+		It("requests a new token when the cached one is about to expire", func() {
+			expiresIn = 1
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-1")).Return(nil, errors.New("fake")).Times(1),
+				httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-2")).Return(nil, errors.New("fake")).Times(1),
+			)
+
+			client.Fetch(ctx, resources.Account, id)
+			time.Sleep(600 * time.Millisecond)
+			client.Fetch(ctx, resources.Account, id)
+		})
+		It("reuses a token living less than the expiry margin for half of its lifetime", func() {
+			expiresIn = 10
+			httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-1")).Return(nil, errors.New("fake")).Times(2)
+
+			client.Fetch(ctx, resources.Account, id)
+			client.Fetch(ctx, resources.Account, id)
+
+			Expect(atomic.LoadInt32(&tokenRequests)).To(Equal(int32(1)))
+		})
+		It("sends the request again with a fresh token when server responses 401", func() {
+			gomock.InOrder(
+				httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-1")).Return(&http.Response{StatusCode: 401}, nil).Times(1),
+				httpClientMock.EXPECT().Do(IsRequestHeader("Authorization", "Bearer token-2")).Return(buildFetchResponse(), nil).Times(1),
+			)
+
+			response, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+		})
+		It("returns the 401 error when the fresh token is also rejected", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 401}, nil).Times(2)
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).Should(
+				MatchError(
+					NewErrResponseStatusCode("GET", fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id), 401)),
+			)
+		})
+	})
+	Context("Requesting tokens concurrently", func() {
+		It("requests only one token at a time", func() {
+			tokenDelay = 50 * time.Millisecond
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					token, err := tokenSource.Token(ctx)
+					Expect(err).To(BeNil())
+					Expect(token).To(Equal("token-1"))
+				}()
+			}
+			wg.Wait()
+
+			Expect(atomic.LoadInt32(&tokenRequests)).To(Equal(int32(1)))
+		})
+	})
+	Context("When something goes wrong", func() {
+		It("returns an error without requesting the API when the token request fails", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+			tokenSource = NewClientCredentialsTokenSource(
+				ClientCredentials{TokenURL: tokenServer.URL, ClientID: clientID, ClientSecret: "wrong"},
+				http.DefaultClient,
+			)
+			client = NewForm3APIClient(baseURL, httpClientMock, WithTokenSource(tokenSource))
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(err).Should(
+				MatchError(NewErrResponseStatusCode("POST", tokenServer.URL, 401)),
+			)
+		})
+		It("returns an error when the token request times out", func() {
+			tokenDelay = 200 * time.Millisecond
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+			tokenSource = NewClientCredentialsTokenSource(
+				ClientCredentials{TokenURL: tokenServer.URL, ClientID: clientID, ClientSecret: clientSecret, RequestTimeout: 50 * time.Millisecond},
+				http.DefaultClient,
+			)
+			client = NewForm3APIClient(baseURL, httpClientMock, WithTokenSource(tokenSource))
+
+			_, err := client.Fetch(ctx, resources.Account, id)
+
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+})
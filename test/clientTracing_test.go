@@ -0,0 +1,133 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Client tracing", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		exporter       *tracetest.InMemoryExporter
+		provider       *sdktrace.TracerProvider
+		client         *Form3Client
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		exporter = tracetest.NewInMemoryExporter()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		client = NewForm3APIClient(baseURL, httpClientMock, WithTracing(provider, nil))
+	})
+
+	spanAttributes := func(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		attributes := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes {
+			attributes[kv.Key] = kv.Value
+		}
+		return attributes
+	}
+
+	It("creates a span with the resource, id and status code", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildFetchResponse(), nil).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		Expect(err).To(BeNil())
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("fetch account"))
+		Expect(spans[0].SpanKind).To(Equal(trace.SpanKindClient))
+		attributes := spanAttributes(spans[0])
+		Expect(attributes["form3.resource"].AsString()).To(Equal("account"))
+		Expect(attributes["form3.resource_id"].AsString()).To(Equal(id))
+		Expect(attributes["http.response.status_code"].AsInt64()).To(Equal(int64(200)))
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+	})
+	It("adds the page attributes to list spans", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(buildListResponse(""), nil).Times(1)
+
+		client.List(ctx, resources.Account, nil, pageNumber, pageSize)
+
+		attributes := spanAttributes(exporter.GetSpans()[0])
+		Expect(attributes["form3.page_number"].AsInt64()).To(Equal(int64(pageNumber)))
+		Expect(attributes["form3.page_size"].AsInt64()).To(Equal(int64(pageSize)))
+		Expect(attributes).NotTo(HaveKey(attribute.Key("form3.resource_id")))
+	})
+	It("injects the traceparent header of the span", func() {
+		var traceparent string
+		httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			traceparent = req.Header.Get("traceparent")
+			return buildFetchResponse(), nil
+		}).Times(1)
+		parentCtx, parent := provider.Tracer("test").Start(ctx, "parent")
+
+		client.Fetch(parentCtx, resources.Account, id)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(traceparent).To(Equal(
+			"00-" + spans[0].SpanContext.TraceID().String() + "-" + spans[0].SpanContext.SpanID().String() + "-01",
+		))
+	})
+	It("records ErrNotFound errors", func() {
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+
+		_, err := client.Fetch(ctx, resources.Account, id)
+
+		span := exporter.GetSpans()[0]
+		Expect(span.Status.Code).To(Equal(codes.Error))
+		Expect(span.Status.Description).To(Equal(err.Error()))
+		Expect(span.Events).To(HaveLen(1))
+		Expect(span.Events[0].Name).To(Equal("exception"))
+		Expect(spanAttributes(span)["http.response.status_code"].AsInt64()).To(Equal(int64(404)))
+	})
+	It("records ErrBadRequest errors", func() {
+		body := `{"error_code": 400, "error_message": "mandatory"}`
+		httpClientMock.EXPECT().Do(gomock.Any()).Return(
+			&http.Response{
+				StatusCode: 400,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			},
+			nil,
+		).Times(1)
+
+		_, err := client.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+		Expect(err).To(BeAssignableToTypeOf(ErrBadRequest{}))
+		span := exporter.GetSpans()[0]
+		Expect(span.Name).To(Equal("create account"))
+		Expect(span.Status.Code).To(Equal(codes.Error))
+		Expect(span.Events[0].Name).To(Equal("exception"))
+	})
+	It("doesn't send trace headers without tracing", func() {
+		client = NewForm3APIClient(baseURL, httpClientMock)
+		httpClientMock.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			Expect(req.Header.Get("traceparent")).To(BeEmpty())
+			return buildFetchResponse(), nil
+		}).Times(1)
+
+		client.Fetch(ctx, resources.Account, id)
+	})
+})
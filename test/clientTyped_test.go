@@ -0,0 +1,176 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+type cardAttributes struct {
+	Number string `json:"number"`
+	Holder string `json:"holder,omitempty"`
+}
+
+func (cardAttributes) ResourceName() resources.ResourceName {
+	return "card"
+}
+
+func (cardAttributes) Endpoint() string {
+	return "organisation/cards"
+}
+
+func (cardAttributes) ResourceType() string {
+	return "cards"
+}
+
+var _ = Describe("Typed client", func() {
+	var (
+		cards          TypedClient[cardAttributes]
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		ctx            = context.Background()
+		cardsURL       = fmt.Sprintf("%s/organisation/cards", baseURL)
+		card           = resources.TypedResource[cardAttributes]{
+			ID:             id,
+			OrganisationID: organisationID,
+			Attributes:     cardAttributes{Number: "4111111111111111", Holder: "Samantha Holder"},
+		}
+		cardData = resources.Resource{
+			ResourceType:   "cards",
+			ID:             id,
+			OrganisationID: organisationID,
+			Attributes: map[string]interface{}{
+				"number": "4111111111111111",
+				"holder": "Samantha Holder",
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		cards = NewTypedClient[cardAttributes](NewForm3APIClient(baseURL, httpClientMock))
+	})
+
+	Context("Converting resources", func() {
+		It("converts a typed resource into a generic resource with its JSON:API type", func() {
+			resource, err := card.Resource()
+
+			Expect(err).To(BeNil())
+			Expect(resource).To(Equal(cardData))
+		})
+		It("converts a generic resource into a typed resource", func() {
+			resource, err := resources.NewTypedResource[cardAttributes](cardData)
+
+			Expect(err).To(BeNil())
+			Expect(resource).To(Equal(card))
+		})
+		It("keeps the relationships through a round trip", func() {
+			withRelationships := cardData
+			withRelationships.Relationships = map[string]interface{}{
+				"account": map[string]interface{}{"data": []interface{}{map[string]interface{}{"type": "accounts", "id": id2}}},
+			}
+
+			typed, err := resources.NewTypedResource[cardAttributes](withRelationships)
+			Expect(err).To(BeNil())
+			resource, err := typed.Resource()
+
+			Expect(err).To(BeNil())
+			Expect(resource).To(Equal(withRelationships))
+		})
+		It("keeps the account resources compatible with the typed resources", func() {
+			account := BuildUKAccountResourceWithCoP(id, organisationID)
+
+			resource, err := account.Resource()
+
+			Expect(err).To(BeNil())
+			Expect(resource.ResourceType).To(Equal(resources.AccountType))
+		})
+	})
+	Context("Requesting the resource endpoint", func() {
+		It("fetches a resource from the registered endpoint", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", cardsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(cardData)), nil,
+			).Times(1)
+
+			response, err := cards.Fetch(ctx, id)
+
+			Expect(err).To(BeNil())
+			Expect(*response).To(Equal(card))
+		})
+		It("keeps the endpoint registered before building the typed client", func() {
+			RegisterEndpoint("card", "issuing/cards")
+			defer RegisterEndpoint("card", "organisation/cards")
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/issuing/cards/%s", baseURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(cardData)), nil,
+			).Times(1)
+
+			_, err := NewTypedClient[cardAttributes](NewForm3APIClient(baseURL, httpClientMock)).Fetch(ctx, id)
+
+			Expect(err).To(BeNil())
+		})
+		It("creates a resource with typed attributes", func() {
+			expectedBody, _ := json.Marshal(resources.NewDataContainer(cardData))
+			expectedReq, _ := http.NewRequest("POST", cardsURL, bytes.NewBuffer(expectedBody))
+			httpClientMock.EXPECT().Do(IsRequestBody(expectedReq)).Return(
+				buildResponse(201, resources.NewDataContainer(cardData)), nil,
+			).Times(1)
+
+			response, err := cards.Create(ctx, card)
+
+			Expect(err).To(BeNil())
+			Expect(*response).To(Equal(card))
+		})
+		It("lists a page of typed resources", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s?page[number]=0&page[size]=100", cardsURL))).Return(
+				buildResponse(200, resources.ListDataContainer{Data: []resources.Resource{cardData, cardData}}), nil,
+			).Times(1)
+
+			page, err := cards.List(ctx, nil, 0, 100)
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]resources.TypedResource[cardAttributes]{card, card}))
+		})
+		It("updates a resource with typed attributes", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", cardsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(cardData)), nil,
+			).Times(1)
+
+			response, err := cards.Update(ctx, card)
+
+			Expect(err).To(BeNil())
+			Expect(*response).To(Equal(card))
+		})
+		It("deletes a resource version", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s?version=%d", cardsURL, id, version))).Return(
+				&http.Response{StatusCode: 204}, nil,
+			).Times(1)
+
+			err := cards.Delete(ctx, id, version)
+
+			Expect(err).To(BeNil())
+		})
+		It("lets the generic client request the registered resource", func() {
+			client := NewForm3APIClient(baseURL, httpClientMock)
+			httpClientMock.EXPECT().Do(IsRequestURL(fmt.Sprintf("%s/%s", cardsURL, id))).Return(
+				buildResponse(200, resources.NewDataContainer(cardData)), nil,
+			).Times(1)
+
+			response, err := client.Fetch(ctx, "card", id)
+
+			Expect(err).To(BeNil())
+			Expect(response.Data).To(Equal(cardData))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+// +build unit
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Account api resource client UPDATE method", func() {
+	var (
+		client         *Form3Client
+		mockCtrl       *gomock.Controller
+		httpClientMock *MockHTTPClient
+		expectedURL    = fmt.Sprintf("%s/organisation/accounts/%s", baseURL, id)
+		ctx            = context.Background()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		httpClientMock = NewMockHTTPClient(mockCtrl)
+		client = NewForm3APIClient(baseURL, httpClientMock)
+	})
+
+	Context("Building the request", func() {
+		It("builds a request with PATCH method", func() {
+			httpClientMock.EXPECT().Do(IsRequestMethod("PATCH")).Return(nil, errors.New("fake")).Times(1)
+
+			client.Update(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+		})
+		It("builds a request with resource endpoint and resource id", func() {
+			httpClientMock.EXPECT().Do(IsRequestURL(expectedURL)).Return(nil, errors.New("fake")).Times(1)
+
+			client.Update(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+		})
+		It("builds a request with dataContainer struct data including the version", func() {
+			accountData := BuildBasicAccountResource(id, organisationID)
+			accountData.Version = 3
+			dataB, _ := json.Marshal(resources.NewDataContainer(accountData))
+			req, _ := http.NewRequest("PATCH", expectedURL, bytes.NewBuffer(dataB))
+			httpClientMock.EXPECT().Do(IsRequestBody(req)).Return(nil, errors.New("fake")).Times(1)
+
+			client.Update(ctx, resources.Account, accountData)
+		})
+	})
+	Context("When getting succesful response", func() {
+		It("returns resourceContainer struct as response data", func() {
+			accountData := BuildBasicAccountResource(id, organisationID)
+			accountData.Version = 1
+			dataBt, _ := json.Marshal(resources.NewDataContainer(accountData))
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewReader(dataBt)),
+				},
+				nil,
+			).Times(1)
+
+			response, err := client.Update(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+
+			Expect(err).To(BeNil())
+			Expect(response.Data.ID).To(Equal(id))
+			Expect(response.Data.Version).To(Equal(1))
+		})
+	})
+	Context("When getting error response from the server", func() {
+		It("returns ErrVersionMismatch error when server responses an error 409", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 409,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"error_message": "invalid version"}`))),
+				},
+				nil,
+			).Times(1)
+
+			response, err := client.Update(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+
+			Expect(response).To(BeNil())
+			Expect(err).Should(
+				MatchError(
+					NewErrVersionMismatch("PATCH", expectedURL, "invalid version")),
+			)
+		})
+		It("returns ErrNotFound error when server responses an error 404", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 404}, nil).Times(1)
+
+			response, err := client.Update(ctx, resources.Account, BuildBasicAccountResource(id, organisationID))
+
+			Expect(response).To(BeNil())
+			Expect(err).Should(MatchError(NewErrNotFound(expectedURL)))
+		})
+	})
+})
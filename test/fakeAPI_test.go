@@ -0,0 +1,212 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/fakeapi"
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+var _ = Describe("Fake account API server", func() {
+	var (
+		fakeServer  *fakeapi.Server
+		apiClient   *Form3Client
+		emptyFilter map[string]interface{}
+		ctx         = context.Background()
+	)
+
+	BeforeEach(func() {
+		fakeServer = fakeapi.NewServer()
+		apiClient = NewForm3APIClient(fakeServer.BaseURL(), http.DefaultClient)
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	Context("Create", func() {
+		It("creates an account and returns it with links", func() {
+			resp, err := apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+			Expect(err).To(BeNil())
+			Expect(resp.Data.ID).To(Equal(id))
+			Expect(resp.Data.Version).To(Equal(0))
+			Expect(resp.Links).NotTo(BeEmpty())
+		})
+		It("returns ErrConflict error when the account id already exists", func() {
+			_, err := apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+			Expect(err).To(BeNil())
+
+			_, err = apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+			Expect(err).Should(
+				MatchError(
+					NewErrConflict(
+						"POST",
+						fmt.Sprintf("%s/organisation/accounts", fakeServer.BaseURL()),
+						"Account cannot be created as it violates a duplicate constraint",
+					)),
+			)
+		})
+		It("returns the same validation error as the real API when country is missing", func() {
+			_, err := apiClient.Create(ctx, resources.Account, BuildUKSampleAccountWithoutCountry(id, organisationID))
+
+			Expect(err).Should(
+				MatchError(
+					NewErrBadRequest("POST",
+						resources.BadRequestData{
+							ErrorMessage: "validation failure list:\nvalidation failure list:\nvalidation failure list:\ncountry in body is required",
+						},
+					)),
+			)
+		})
+		It("returns ErrBadRequest error when the id is not a uuid", func() {
+			_, err := apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP("not-a-uuid", organisationID))
+
+			Expect(err).To(BeAssignableToTypeOf(ErrBadRequest{}))
+		})
+	})
+	Context("Fetch", func() {
+		It("returns ErrNotFound error when the account doesn't exist", func() {
+			_, err := apiClient.Fetch(ctx, resources.Account, id)
+
+			Expect(err).Should(
+				MatchError(
+					NewErrNotFound(fmt.Sprintf("%s/organisation/accounts/%s", fakeServer.BaseURL(), id))),
+			)
+		})
+		It("returns ErrBadRequest error when the id is not a uuid", func() {
+			_, err := apiClient.Fetch(ctx, resources.Account, "not-a-uuid")
+
+			Expect(err).Should(
+				MatchError(
+					NewErrBadRequest("GET", resources.BadRequestData{ErrorMessage: "id is not a valid uuid"})),
+			)
+		})
+	})
+	Context("List", func() {
+		BeforeEach(func() {
+			apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+			frAccount := BuildUKAccountWithoutCoP(id2, organisationID2)
+			frAccount.Attributes["country"] = "FR"
+			apiClient.Create(ctx, resources.Account, frAccount)
+		})
+
+		It("returns the requested page", func() {
+			resp, err := apiClient.List(ctx, resources.Account, emptyFilter, 1, 1)
+
+			Expect(err).To(BeNil())
+			Expect(len(resp.Data)).To(Equal(1))
+			Expect(resp.Data[0].ID).To(Equal(id2))
+			Expect(resp.Links).NotTo(HaveKey("next"))
+		})
+		It("returns an empty page when the page is out of range, however big", func() {
+			for _, page := range [][2]int{{math.MaxInt64, 100}, {2, math.MaxInt64}, {math.MaxInt64, math.MaxInt64}} {
+				resp, err := apiClient.List(ctx, resources.Account, emptyFilter, page[0], page[1])
+
+				Expect(err).To(BeNil())
+				Expect(resp.Data).To(BeEmpty())
+				Expect(resp.Links).NotTo(HaveKey("next"))
+			}
+		})
+		It("returns only the accounts matching the filter", func() {
+			filter := map[string]interface{}{"country": []string{"FR", "DE"}}
+
+			resp, err := apiClient.List(ctx, resources.Account, filter, 0, 100)
+
+			Expect(err).To(BeNil())
+			Expect(len(resp.Data)).To(Equal(1))
+			Expect(resp.Data[0].ID).To(Equal(id2))
+		})
+		It("returns every account following the next links", func() {
+			it := apiClient.ListAll(ctx, resources.Account, emptyFilter, 1)
+			ids := []string{}
+			for it.Next() {
+				ids = append(ids, it.Resource().ID)
+			}
+
+			Expect(it.Err()).To(BeNil())
+			Expect(ids).To(Equal([]string{id, id2}))
+		})
+	})
+	Context("Update", func() {
+		It("updates the attributes and increases the version", func() {
+			apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+			account := resources.NewAccount(id, organisationID, map[string]interface{}{"bank_id": "400302"})
+
+			resp, err := apiClient.Update(ctx, resources.Account, account)
+
+			Expect(err).To(BeNil())
+			Expect(resp.Data.Version).To(Equal(1))
+			Expect(resp.Data.Attributes["bank_id"]).To(Equal("400302"))
+			Expect(resp.Data.Attributes["country"]).To(Equal("GB"))
+		})
+		It("returns ErrVersionMismatch error when the version is not the current one", func() {
+			apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+			account := BuildUKAccountWithCoP(id, organisationID)
+			account.Version = 1
+
+			_, err := apiClient.Update(ctx, resources.Account, account)
+
+			Expect(errors.Is(err, ErrVersionMismatch{})).To(BeTrue())
+		})
+		It("updates typed accounts", func() {
+			created, err := CreateAccount(ctx, apiClient, BuildUKAccountResourceWithCoP(id, organisationID))
+			Expect(err).To(BeNil())
+			created.Attributes.Name = []string{"Samantha Holder-Smith"}
+
+			updated, err := UpdateAccount(ctx, apiClient, *created)
+
+			Expect(err).To(BeNil())
+			Expect(updated.Version).To(Equal(1))
+			Expect(updated.Attributes.Name).To(Equal([]string{"Samantha Holder-Smith"}))
+		})
+		It("updates typed boolean attributes back to false", func() {
+			account := BuildUKAccountResourceWithCoP(id, organisationID)
+			account.Attributes.Switched = resources.Bool(true)
+			created, err := CreateAccount(ctx, apiClient, account)
+			Expect(err).To(BeNil())
+			created.Attributes.Switched = resources.Bool(false)
+
+			updated, err := UpdateAccount(ctx, apiClient, *created)
+
+			Expect(err).To(BeNil())
+			Expect(updated.Attributes.Switched).To(Equal(resources.Bool(false)))
+		})
+	})
+	Context("Delete", func() {
+		It("deletes the account with the current version", func() {
+			apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+			err := apiClient.Delete(ctx, resources.Account, id, 0)
+
+			Expect(err).To(BeNil())
+			_, err = apiClient.Fetch(ctx, resources.Account, id)
+			Expect(errors.Is(err, ErrNotFound{})).To(BeTrue())
+		})
+		It("returns ErrVersionMismatch error when the version doesn't match", func() {
+			apiClient.Create(ctx, resources.Account, BuildUKAccountWithCoP(id, organisationID))
+
+			err := apiClient.Delete(ctx, resources.Account, id, 3)
+
+			Expect(err).Should(
+				MatchError(
+					NewErrVersionMismatch(
+						"DELETE",
+						fmt.Sprintf("%s/organisation/accounts/%s?version=3", fakeServer.BaseURL(), id),
+						"invalid version",
+					)),
+			)
+		})
+	})
+})
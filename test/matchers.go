@@ -72,3 +72,19 @@ func (i *isRequestHeaderValues) Matches(x interface{}) bool {
 func (i *isRequestHeaderValues) String() string {
 	return fmt.Sprintf("Headers : %s", i.r.Header)
 }
+
+type isRequestHeader struct{ name, value string }
+
+// Matcher to check if http Request header has the value
+func IsRequestHeader(name, value string) gomock.Matcher {
+	return &isRequestHeader{name, value}
+}
+
+func (i *isRequestHeader) Matches(x interface{}) bool {
+	req := x.(*http.Request)
+	return req.Header.Get(i.name) == i.value
+}
+
+func (i *isRequestHeader) String() string {
+	return fmt.Sprintf("Header %s: %s", i.name, i.value)
+}
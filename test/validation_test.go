@@ -0,0 +1,228 @@
+// +build unit
+
+package test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+
+	. "github.com/onsi/gomega"
+	. "github.com/regiluze/form3-account-api-client/client"
+	"github.com/regiluze/form3-account-api-client/resources"
+	"github.com/regiluze/form3-account-api-client/validation"
+)
+
+var _ = Describe("Account client side validation", func() {
+	validateAttributes := func(attributes map[string]interface{}) validation.Errors {
+		err := validation.ValidateAccount(resources.NewAccount(id, organisationID, attributes))
+		if err == nil {
+			return nil
+		}
+		var errs validation.Errors
+		Expect(errors.As(err, &errs)).To(BeTrue())
+		return errs
+	}
+
+	Context("Valid accounts", func() {
+		It("accepts a UK account without CoP", func() {
+			Expect(validateAttributes(buildUKAccountWithoutCoP())).To(BeNil())
+		})
+		It("accepts a UK account with CoP", func() {
+			Expect(validateAttributes(buildUKAccountWithCoP())).To(BeNil())
+		})
+		It("accepts a German account", func() {
+			Expect(validateAttributes(map[string]interface{}{
+				"country":        "DE",
+				"bank_id":        "37040044",
+				"bank_id_code":   "DEBLZ",
+				"account_number": "0532013",
+			})).To(BeNil())
+		})
+		It("accepts countries without specific rules", func() {
+			Expect(validateAttributes(map[string]interface{}{"country": "JP"})).To(BeNil())
+		})
+	})
+	Context("Invalid accounts", func() {
+		It("returns a required error when country is missing", func() {
+			errs := validateAttributes(map[string]interface{}{"bank_id": "400300"})
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.country", Rule: validation.RuleRequired, Message: "country is required"},
+			}))
+		})
+		It("returns errors for the id and organisation id when they aren't uuids", func() {
+			err := validation.ValidateAccount(resources.NewAccount("1", "2", buildUKAccountWithoutCoP()))
+
+			errs := err.(validation.Errors)
+			Expect(errs.Field("id")).To(HaveLen(1))
+			Expect(errs.Field("organisation_id")).To(HaveLen(1))
+		})
+		It("returns the GB required fields errors", func() {
+			errs := validateAttributes(map[string]interface{}{"country": "GB"})
+
+			Expect(errs).To(ConsistOf(
+				validation.FieldError{Field: "attributes.bank_id", Rule: validation.RuleRequired, Message: "bank_id is required for country GB"},
+				validation.FieldError{Field: "attributes.bic", Rule: validation.RuleRequired, Message: "bic is required for country GB"},
+			))
+		})
+		It("returns a required error for the bank id code of AU without bank id", func() {
+			errs := validateAttributes(map[string]interface{}{
+				"country": "AU",
+				"bic":     "NATAAU33",
+			})
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.bank_id_code", Rule: validation.RuleRequired, Message: "bank_id_code is required for country AU"},
+			}))
+		})
+		It("returns format errors for the country bank id and account number", func() {
+			errs := validateAttributes(map[string]interface{}{
+				"country":        "FR",
+				"bank_id":        "123",
+				"bank_id_code":   "FR",
+				"account_number": "1",
+			})
+
+			Expect(errs.Field("attributes.bank_id")[0].Rule).To(Equal(validation.RuleFormat))
+			Expect(errs.Field("attributes.account_number")[0].Rule).To(Equal(validation.RuleFormat))
+		})
+		It("returns a value error when the bank id code is not the country one", func() {
+			errs := validateAttributes(map[string]interface{}{
+				"country":      "ES",
+				"bank_id":      "12345678",
+				"bank_id_code": "GBDSC",
+			})
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.bank_id_code", Rule: validation.RuleValue, Message: "bank_id_code must be ESNCC for country ES"},
+			}))
+		})
+		It("returns a not supported error for IBAN in countries without IBAN", func() {
+			errs := validateAttributes(map[string]interface{}{
+				"country": "US",
+				"bank_id": "021000021",
+				"bic":     "CHASUS33",
+				"iban":    "GB33BUKB20201555555555",
+			})
+
+			Expect(errs.Field("attributes.iban")[0].Rule).To(Equal(validation.RuleNotSupported))
+		})
+		It("returns generic format errors", func() {
+			errs := validateAttributes(map[string]interface{}{
+				"country":                "GB",
+				"bank_id":                "400300",
+				"bank_id_code":           "GBDSC",
+				"bic":                    "NWBK",
+				"base_currency":          "pounds",
+				"account_classification": "Corporate",
+				"name":                   []string{"a", "b", "c", "d", "e"},
+			})
+
+			Expect(errs.Field("attributes.bic")).To(HaveLen(1))
+			Expect(errs.Field("attributes.base_currency")).To(HaveLen(1))
+			Expect(errs.Field("attributes.account_classification")).To(HaveLen(1))
+			Expect(errs.Field("attributes.name")[0].Rule).To(Equal(validation.RuleMaxItems))
+		})
+	})
+	Context("Server validation messages", func() {
+		It("parses the attributes failures of the nested failure list", func() {
+			errs := validation.ParseServerMessage(
+				"validation failure list:\nvalidation failure list:\nvalidation failure list:\n" +
+					"country in body is required\nbic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'",
+			)
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.country", Rule: validation.RuleRequired, Message: "country in body is required"},
+				{Field: "attributes.bic", Rule: validation.RuleFormat, Message: "bic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'"},
+			}))
+		})
+		It("parses the resource failures", func() {
+			errs := validation.ParseServerMessage(
+				"validation failure list:\nvalidation failure list:\n" +
+					"id in body must be of type uuid: \"1\"\ntype in body should be one of [accounts]",
+			)
+
+			Expect(errs.Field("id")[0].Rule).To(Equal(validation.RuleFormat))
+			Expect(errs.Field("type")[0].Rule).To(Equal(validation.RuleValue))
+		})
+		It("parses the failures of every level when the failure lists are interleaved", func() {
+			errs := validation.ParseServerMessage(
+				"validation failure list:\nvalidation failure list:\nid in body is required\n" +
+					"validation failure list:\ncountry in body is required",
+			)
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "id", Rule: validation.RuleRequired, Message: "id in body is required"},
+				{Field: "attributes.country", Rule: validation.RuleRequired, Message: "country in body is required"},
+			}))
+		})
+		It("keeps the failures it doesn't know", func() {
+			errs := validation.ParseServerMessage(
+				"validation failure list:\nvalidation failure list:\nvalidation failure list:\nname in body is odd\nsomething failed",
+			)
+
+			Expect(errs).To(Equal(validation.Errors{
+				{Field: "attributes.name", Rule: validation.RuleUnknown, Message: "name in body is odd"},
+				{Field: "", Rule: validation.RuleUnknown, Message: "something failed"},
+			}))
+		})
+		It("returns no errors when the message isn't a failure list", func() {
+			Expect(validation.ParseServerMessage("id is not a valid uuid")).To(BeNil())
+		})
+		It("exposes the field errors on ErrBadRequest", func() {
+			mockCtrl := gomock.NewController(GinkgoT())
+			httpClientMock := NewMockHTTPClient(mockCtrl)
+			client := NewForm3APIClient(baseURL, httpClientMock)
+			body := `{"error_message": "validation failure list:\nvalidation failure list:\nvalidation failure list:\ncountry in body is required"}`
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(
+				&http.Response{
+					StatusCode: 400,
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+				},
+				nil,
+			).Times(1)
+
+			_, err := client.Create(context.Background(), resources.Account, BuildUKSampleAccountWithoutCountry(id, organisationID))
+
+			var badRequest ErrBadRequest
+			Expect(errors.As(err, &badRequest)).To(BeTrue())
+			Expect(badRequest.ErrorData().ErrorMessage).To(HaveSuffix("country in body is required"))
+			Expect(badRequest.FieldErrors().Field("attributes.country")[0].Rule).To(Equal(validation.RuleRequired))
+		})
+	})
+	Context("Validating in the client", func() {
+		var (
+			mockCtrl       *gomock.Controller
+			httpClientMock *MockHTTPClient
+			client         *Form3Client
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			httpClientMock = NewMockHTTPClient(mockCtrl)
+			client = NewForm3APIClient(baseURL, httpClientMock, WithValidator(validation.Validate))
+		})
+
+		It("returns the validation errors without requesting the server", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Times(0)
+
+			response, err := client.Create(context.Background(), resources.Account, BuildUKSampleAccountWithoutCountry(id, organisationID))
+
+			Expect(response).To(BeNil())
+			var errs validation.Errors
+			Expect(errors.As(err, &errs)).To(BeTrue())
+			Expect(errs.Field("attributes.country")).To(HaveLen(1))
+		})
+		It("sends valid accounts", func() {
+			httpClientMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("fake")).Times(1)
+
+			client.Create(context.Background(), resources.Account, BuildUKAccountWithCoP(id, organisationID))
+		})
+	})
+})
@@ -0,0 +1,224 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const (
+	maxNameItems  = 4
+	maxNameLength = 140
+)
+
+var (
+	uuidRegexp         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	countryRegexp      = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyRegexp     = regexp.MustCompile(`^[A-Z]{3}$`)
+	bicRegexp          = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanRegexp         = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{1,30}$`)
+	classificationList = []string{"Personal", "Business"}
+)
+
+// countryRule holds the Form3 account rules of a country. A nil pattern
+// means the attribute is not supported for the country. The bank id code
+// is required along with the bank id, or always when bankIDCodeRequired.
+type countryRule struct {
+	bankIDRequired       bool
+	bankIDPattern        *regexp.Regexp
+	bankIDCode           string
+	bankIDCodeRequired   bool
+	bicRequired          bool
+	accountNumberPattern *regexp.Regexp
+	ibanNotSupported     bool
+}
+
+var countryRules = map[string]countryRule{
+	"AU": {bankIDPattern: regexp.MustCompile(`^[0-9]{6}$`), bankIDCode: "AUBSB", bankIDCodeRequired: true, bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{6,10}$`), ibanNotSupported: true},
+	"BE": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{3}$`), bankIDCode: "BE", accountNumberPattern: regexp.MustCompile(`^[0-9]{7}$`)},
+	"CA": {bankIDPattern: regexp.MustCompile(`^0[0-9]{8}$`), bankIDCode: "CACPA", bankIDCodeRequired: true, bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{7,12}$`), ibanNotSupported: true},
+	"CH": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{5}$`), bankIDCode: "CHBCC", accountNumberPattern: regexp.MustCompile(`^[0-9]{12}$`)},
+	"DE": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{8}$`), bankIDCode: "DEBLZ", accountNumberPattern: regexp.MustCompile(`^[0-9]{7}$`)},
+	"ES": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{8}$`), bankIDCode: "ESNCC", accountNumberPattern: regexp.MustCompile(`^[0-9]{10}$`)},
+	"FR": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9A-Z]{10}$`), bankIDCode: "FR", accountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{10}$`)},
+	"GB": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{6}$`), bankIDCode: "GBDSC", bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{8}$`)},
+	"GR": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{7}$`), bankIDCode: "GRBIC", accountNumberPattern: regexp.MustCompile(`^[0-9]{16}$`)},
+	"HK": {bankIDPattern: regexp.MustCompile(`^[0-9]{3}$`), bankIDCode: "HKNCC", bankIDCodeRequired: true, bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{9,12}$`), ibanNotSupported: true},
+	"IT": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9A-Z]{10,11}$`), bankIDCode: "ITNCC", accountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{12}$`)},
+	"LU": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{3}$`), bankIDCode: "LULUX", accountNumberPattern: regexp.MustCompile(`^[0-9A-Z]{13}$`)},
+	"NL": {bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{10}$`)},
+	"PL": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{8}$`), bankIDCode: "PLKNR", accountNumberPattern: regexp.MustCompile(`^[0-9]{16}$`)},
+	"PT": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{8}$`), bankIDCode: "PTNCC", accountNumberPattern: regexp.MustCompile(`^[0-9]{11}$`)},
+	"US": {bankIDRequired: true, bankIDPattern: regexp.MustCompile(`^[0-9]{9}$`), bankIDCode: "USABA", bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[0-9]{6,17}$`), ibanNotSupported: true},
+}
+
+// Validate validates the resources the package has rules for, it matches
+// the client.Validator type so it can be plugged into the client.
+func Validate(resourceName resources.ResourceName, resource resources.Resource) error {
+	switch resourceName {
+	case resources.Account:
+		return ValidateAccount(resource)
+	case resources.Organisation:
+		return ValidateOrganisation(resource)
+	case resources.Payment:
+		return ValidatePayment(resource)
+	}
+	return nil
+}
+
+// ValidateAccount validates an account resource, as built with
+// resources.NewAccount, returning Errors when any field isn't valid.
+func ValidateAccount(resource resources.Resource) error {
+	errs := validateResourceIdentity(resource, resources.AccountType)
+	attributes, err := resources.NewAccountAttributes(resource.Attributes)
+	if err != nil {
+		errs = append(errs, FieldError{"attributes", RuleFormat, err.Error()})
+	} else {
+		errs = append(errs, ValidateAccountAttributes(attributes)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateResourceIdentity checks the id, organisation id and type of a
+// resource, the fields every resource has.
+func validateResourceIdentity(resource resources.Resource, expectedType string) Errors {
+	errs := Errors{}
+	if !uuidRegexp.MatchString(resource.ID) {
+		errs = append(errs, FieldError{"id", RuleFormat, "id must be a uuid"})
+	}
+	if !uuidRegexp.MatchString(resource.OrganisationID) {
+		errs = append(errs, FieldError{"organisation_id", RuleFormat, "organisation_id must be a uuid"})
+	}
+	if resource.ResourceType != expectedType {
+		errs = append(errs, FieldError{"type", RuleValue, fmt.Sprintf("type must be %s", expectedType)})
+	}
+	return errs
+}
+
+// ValidateAccountAttributes validates the account attributes with the
+// generic rules and the rules of the account country.
+func ValidateAccountAttributes(attributes resources.AccountAttributes) Errors {
+	errs := Errors{}
+	if attributes.Country == "" {
+		return append(errs, FieldError{attributeField("country"), RuleRequired, "country is required"})
+	}
+	if !countryRegexp.MatchString(attributes.Country) {
+		errs = append(errs, FieldError{attributeField("country"), RuleFormat, "country must be an ISO 3166-1 code"})
+	}
+	errs = append(errs, checkPattern("base_currency", attributes.BaseCurrency, currencyRegexp, "an ISO 4217 code")...)
+	errs = append(errs, checkPattern("bic", attributes.Bic, bicRegexp, "a SWIFT BIC")...)
+	errs = append(errs, checkPattern("iban", attributes.Iban, ibanRegexp, "an IBAN")...)
+	errs = append(errs, checkName("name", attributes.Name)...)
+	errs = append(errs, checkName("alternative_names", attributes.AlternativeNames)...)
+	if attributes.AccountClassification != "" && !containsString(classificationList, attributes.AccountClassification) {
+		errs = append(errs, FieldError{
+			attributeField("account_classification"),
+			RuleValue,
+			fmt.Sprintf("account_classification must be one of %v", classificationList),
+		})
+	}
+
+	rule, ok := countryRules[attributes.Country]
+	if !ok {
+		return errs
+	}
+	return append(errs, rule.validate(attributes)...)
+}
+
+func (r countryRule) validate(attributes resources.AccountAttributes) Errors {
+	errs := Errors{}
+	country := attributes.Country
+	switch {
+	case r.bankIDPattern == nil && attributes.BankID != "":
+		errs = append(errs, notSupported("bank_id", country))
+	case r.bankIDRequired && attributes.BankID == "":
+		errs = append(errs, required("bank_id", country))
+	case attributes.BankID != "" && !r.bankIDPattern.MatchString(attributes.BankID):
+		errs = append(errs, FieldError{
+			attributeField("bank_id"),
+			RuleFormat,
+			fmt.Sprintf("bank_id must match %s for country %s", r.bankIDPattern, country),
+		})
+	}
+
+	switch {
+	case r.bankIDCode == "" && attributes.BankIDCode != "":
+		errs = append(errs, notSupported("bank_id_code", country))
+	case r.bankIDCode != "" && (r.bankIDCodeRequired || attributes.BankID != "") && attributes.BankIDCode == "":
+		errs = append(errs, required("bank_id_code", country))
+	case attributes.BankIDCode != "" && attributes.BankIDCode != r.bankIDCode:
+		errs = append(errs, FieldError{
+			attributeField("bank_id_code"),
+			RuleValue,
+			fmt.Sprintf("bank_id_code must be %s for country %s", r.bankIDCode, country),
+		})
+	}
+
+	if r.bicRequired && attributes.Bic == "" {
+		errs = append(errs, required("bic", country))
+	}
+	if attributes.AccountNumber != "" && !r.accountNumberPattern.MatchString(attributes.AccountNumber) {
+		errs = append(errs, FieldError{
+			attributeField("account_number"),
+			RuleFormat,
+			fmt.Sprintf("account_number must match %s for country %s", r.accountNumberPattern, country),
+		})
+	}
+	if r.ibanNotSupported && attributes.Iban != "" {
+		errs = append(errs, notSupported("iban", country))
+	}
+	return errs
+}
+
+func checkPattern(name, value string, pattern *regexp.Regexp, description string) Errors {
+	if value == "" || pattern.MatchString(value) {
+		return nil
+	}
+	return Errors{{attributeField(name), RuleFormat, fmt.Sprintf("%s must be %s", name, description)}}
+}
+
+func checkName(name string, lines []string) Errors {
+	errs := Errors{}
+	if len(lines) > maxNameItems {
+		errs = append(errs, FieldError{
+			attributeField(name),
+			RuleMaxItems,
+			fmt.Sprintf("%s can't have more than %d lines", name, maxNameItems),
+		})
+	}
+	for _, line := range lines {
+		if len(line) > maxNameLength {
+			errs = append(errs, FieldError{
+				attributeField(name),
+				RuleMaxLength,
+				fmt.Sprintf("%s lines can't be longer than %d characters", name, maxNameLength),
+			})
+		}
+	}
+	return errs
+}
+
+func required(name, country string) FieldError {
+	return FieldError{attributeField(name), RuleRequired, fmt.Sprintf("%s is required for country %s", name, country)}
+}
+
+func notSupported(name, country string) FieldError {
+	return FieldError{attributeField(name), RuleNotSupported, fmt.Sprintf("%s is not supported for country %s", name, country)}
+}
+
+func attributeField(name string) string {
+	return "attributes." + name
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
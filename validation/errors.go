@@ -0,0 +1,54 @@
+// Package validation checks resources on the client side with the Form3
+// rules, to get field level errors before sending any request.
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	RuleRequired     = "required"
+	RuleFormat       = "format"
+	RuleValue        = "value"
+	RuleNotSupported = "not_supported"
+	RuleMaxItems     = "max_items"
+	RuleMaxLength    = "max_length"
+	// RuleUnknown is the rule of the server validation messages not known
+	// by the client.
+	RuleUnknown = "unknown"
+)
+
+// FieldError is a validation error of a resource field, Field is the JSON
+// path of the field like attributes.bank_id.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Errors is the list of field errors of a resource.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := []string{}
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Error())
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
+
+// Field returns the errors of a field.
+func (e Errors) Field(field string) Errors {
+	fieldErrors := Errors{}
+	for _, fieldError := range e {
+		if fieldError.Field == field {
+			fieldErrors = append(fieldErrors, fieldError)
+		}
+	}
+	return fieldErrors
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+// ValidateOrganisation validates an organisation unit resource, as built
+// with resources.NewOrganisation, returning Errors when any field isn't
+// valid.
+func ValidateOrganisation(resource resources.Resource) error {
+	errs := validateResourceIdentity(resource, resources.OrganisationType)
+	organisation, err := resources.NewOrganisationResource(resource)
+	if err != nil {
+		errs = append(errs, FieldError{"attributes", RuleFormat, err.Error()})
+	} else {
+		errs = append(errs, ValidateOrganisationAttributes(organisation.Attributes)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateOrganisationAttributes validates the organisation unit attributes.
+func ValidateOrganisationAttributes(attributes resources.OrganisationAttributes) Errors {
+	errs := Errors{}
+	if attributes.Name == "" {
+		errs = append(errs, FieldError{attributeField("name"), RuleRequired, "name is required"})
+	} else if len(attributes.Name) > maxNameLength {
+		errs = append(errs, FieldError{
+			attributeField("name"),
+			RuleMaxLength,
+			fmt.Sprintf("name can't be longer than %d characters", maxNameLength),
+		})
+	}
+	if attributes.Country != "" && !countryRegexp.MatchString(attributes.Country) {
+		errs = append(errs, FieldError{attributeField("country"), RuleFormat, "country must be an ISO 3166-1 code"})
+	}
+	return errs
+}
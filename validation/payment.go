@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/regiluze/form3-account-api-client/resources"
+)
+
+const processingDateLayout = "2006-01-02"
+
+// amountRegexp matches the decimal numbers with a non zero digit, so zero
+// amounts like "0.00" aren't valid.
+var amountRegexp = regexp.MustCompile(`^([0-9]*[1-9][0-9]*(\.[0-9]+)?|[0-9]+\.[0-9]*[1-9][0-9]*)$`)
+
+// ValidatePayment validates a payment resource, as built with
+// resources.NewPayment, returning Errors when any field isn't valid.
+func ValidatePayment(resource resources.Resource) error {
+	errs := validateResourceIdentity(resource, resources.PaymentType)
+	payment, err := resources.NewPaymentResource(resource)
+	if err != nil {
+		errs = append(errs, FieldError{"attributes", RuleFormat, err.Error()})
+	} else {
+		errs = append(errs, ValidatePaymentAttributes(payment.Attributes)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidatePaymentAttributes validates the payment attributes and the
+// account references of its parties.
+func ValidatePaymentAttributes(attributes resources.PaymentAttributes) Errors {
+	errs := Errors{}
+	if attributes.Amount == "" {
+		errs = append(errs, FieldError{attributeField("amount"), RuleRequired, "amount is required"})
+	}
+	errs = append(errs, checkPattern("amount", attributes.Amount, amountRegexp, "a positive decimal number")...)
+	if attributes.Currency == "" {
+		errs = append(errs, FieldError{attributeField("currency"), RuleRequired, "currency is required"})
+	}
+	errs = append(errs, checkPattern("currency", attributes.Currency, currencyRegexp, "an ISO 4217 code")...)
+	if attributes.ProcessingDate != "" {
+		if _, err := time.Parse(processingDateLayout, attributes.ProcessingDate); err != nil {
+			errs = append(errs, FieldError{attributeField("processing_date"), RuleFormat, "processing_date must be a YYYY-MM-DD date"})
+		}
+	}
+	errs = append(errs, checkPaymentParty("beneficiary_party", attributes.BeneficiaryParty)...)
+	errs = append(errs, checkPaymentParty("debtor_party", attributes.DebtorParty)...)
+	return errs
+}
+
+func checkPaymentParty(name string, party resources.PaymentParty) Errors {
+	errs := Errors{}
+	if party.AccountNumber == "" {
+		errs = append(errs, FieldError{
+			attributeField(name + ".account_number"),
+			RuleRequired,
+			fmt.Sprintf("%s.account_number is required", name),
+		})
+	}
+	if party.AccountNumberCode == resources.AccountNumberCodeIBAN {
+		errs = append(errs, checkPattern(name+".account_number", party.AccountNumber, ibanRegexp, "an IBAN")...)
+	}
+	return errs
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+const failureListLine = "validation failure list:"
+
+// serverMessageRules maps the server validation messages, without the field
+// name, to the field error rules.
+var serverMessageRules = []struct {
+	pattern *regexp.Regexp
+	rule    string
+}{
+	{regexp.MustCompile(`^is required`), RuleRequired},
+	{regexp.MustCompile(`^(should match|must be of type)`), RuleFormat},
+	{regexp.MustCompile(`^should be one of`), RuleValue},
+	{regexp.MustCompile(`^should have at most \d+ items`), RuleMaxItems},
+	{regexp.MustCompile(`^should be at most \d+ chars long`), RuleMaxLength},
+}
+
+// ParseServerMessage parses the "validation failure list" error message of
+// the server into field errors, with the same field paths as the client side
+// validation. Each failure list line, wherever it is, starts a level deeper
+// in the resource: the resource fields are at the second level and the
+// attributes at the third one. It returns nil when the message isn't a
+// failure list.
+func ParseServerMessage(message string) Errors {
+	lines := strings.Split(message, "\n")
+	if strings.TrimSpace(lines[0]) != failureListLine {
+		return nil
+	}
+	depth := 0
+	errs := Errors{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == failureListLine {
+			depth++
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		prefix := ""
+		if depth > 2 {
+			prefix = "attributes."
+		}
+		errs = append(errs, parseServerFailure(prefix, line))
+	}
+	return errs
+}
+
+// parseServerFailure parses a failure like "country in body is required".
+func parseServerFailure(prefix, failure string) FieldError {
+	parts := strings.SplitN(failure, " in body ", 2)
+	if len(parts) != 2 {
+		return FieldError{Rule: RuleUnknown, Message: failure}
+	}
+	fieldError := FieldError{prefix + parts[0], RuleUnknown, failure}
+	for _, messageRule := range serverMessageRules {
+		if messageRule.pattern.MatchString(parts[1]) {
+			fieldError.Rule = messageRule.rule
+			break
+		}
+	}
+	return fieldError
+}